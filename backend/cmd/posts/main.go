@@ -0,0 +1,157 @@
+// posts 命令行工具：將文章導出為 Markdown 壓縮包，或從壓縮包、Markdown 文件導入
+//
+//	go run ./cmd/posts export -o posts.zip [-status published] [-author-id 1]
+//	go run ./cmd/posts import [-as admin] posts.zip post.md ...
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"backend/config"
+	"backend/internal/database"
+	"backend/internal/models"
+	"backend/internal/services"
+
+	"gorm.io/gorm/logger"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `用法:
+  posts export -o posts.zip [-status 狀態] [-author-id 作者ID]
+  posts import [-as 用戶名] 文件.zip|文件.md ...`)
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	config.LoadConfig()
+	database.InitDB()
+	database.DB.Logger = logger.Default.LogMode(logger.Warn)
+
+	var err error
+	switch os.Args[1] {
+	case "export":
+		err = runExport(os.Args[2:])
+	case "import":
+		err = runImport(os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	output := fs.String("o", "posts.zip", "輸出文件")
+	status := fs.String("status", "", "只導出指定狀態的文章")
+	authorID := fs.Uint("author-id", 0, "只導出指定作者的文章")
+	fs.Parse(args)
+
+	if *status != "" && !services.IsValidPostStatus(*status) {
+		return services.ErrInvalidPostStatus
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	filter := services.PostFilter{Status: *status, AuthorID: uint(*authorID)}
+	count, err := services.NewPostService().ExportPosts(f, filter)
+	if err != nil {
+		return err
+	}
+	log.Printf("已導出 %d 篇文章到 %s", count, *output)
+	return nil
+}
+
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	as := fs.String("as", "", "執行導入的用戶名，作者不存在的文章歸屬於此用戶（默認為第一個管理員）")
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		usage()
+	}
+
+	actor, err := importActor(*as)
+	if err != nil {
+		return err
+	}
+
+	postService := services.NewPostService()
+	report := &services.ImportReport{Files: []services.ImportFileResult{}}
+	for _, name := range fs.Args() {
+		r, err := importPath(postService, name, actor)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		report.Total += r.Total
+		report.Created += r.Created
+		report.Updated += r.Updated
+		report.Failed += r.Failed
+		report.Files = append(report.Files, r.Files...)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+	log.Printf("共 %d 個文件：新建 %d，更新 %d，失敗 %d", report.Total, report.Created, report.Updated, report.Failed)
+	return nil
+}
+
+// 導入單個路徑，zip 按壓縮包處理，其餘按 Markdown 文件處理
+func importPath(postService *services.PostService, name string, actor services.Actor) (*services.ImportReport, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if strings.ToLower(filepath.Ext(name)) == ".zip" {
+		info, err := f.Stat()
+		if err != nil {
+			return nil, err
+		}
+		return postService.ImportArchive(f, info.Size(), actor)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(f, services.MaxImportFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > services.MaxImportFileSize {
+		return nil, fmt.Errorf("文件超過 %d MB", services.MaxImportFileSize>>20)
+	}
+	return postService.ImportMarkdown(filepath.Base(name), data, actor), nil
+}
+
+// 確定執行導入的用戶
+func importActor(username string) (services.Actor, error) {
+	var user models.User
+	query := database.DB.Order("id ASC")
+	if username != "" {
+		query = query.Where("username = ?", username)
+	} else {
+		query = query.Where("role = ?", services.RoleAdmin)
+	}
+	if err := query.First(&user).Error; err != nil {
+		return services.Actor{}, errors.New("找不到執行導入的用戶")
+	}
+	return services.Actor{UserID: user.ID, Role: user.Role}, nil
+}
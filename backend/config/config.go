@@ -4,6 +4,8 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -11,12 +13,65 @@ import (
 type Config struct {
 	Port       int
 	GinMode    string
+	SiteURL    string // 站點對外地址，用於生成絕對鏈接
 	DBType     string
 	DBPath     string
 	JWTSecret  string
 	JWTExpires string
 	AdminEmail string
 	AdminPass  string
+
+	// 文件存儲
+	StorageBackend   string // local 或 s3
+	StoragePath      string
+	S3Endpoint       string
+	S3Region         string
+	S3Bucket         string
+	S3AccessKey      string
+	S3SecretKey      string
+	S3PathStyle      bool
+	MaxUploadSize    int64 // 字節
+	UserStorageQuota int64 // 字節
+	SignedURLExpires time.Duration
+
+	// 草稿預覽鏈接
+	PreviewExpires    time.Duration // 默認有效期
+	PreviewMaxExpires time.Duration // 最長有效期
+
+	PostGrantExpires time.Duration // 密碼保護文章解鎖後的訪問有效期
+
+	// 密碼保護文章的解鎖嘗試限制：同一用戶或 IP 對同一篇文章失敗達到次數後鎖定一段時間
+	PostUnlockMaxAttempts int
+	PostUnlockLockout     time.Duration
+
+	// 相關文章
+	RelatedPostsCount    int           // 默認返回數量
+	RelatedExcludeAuthor bool          // 默認排除同一作者的文章
+	RelatedIndexInterval time.Duration // 增量更新文本相似度的間隔
+
+	// 瀏覽量統計
+	ViewFlushInterval time.Duration // 批量寫入間隔
+	ViewDedupWindow   time.Duration // 同一訪客在此時間內重複瀏覽只計一次
+
+	AnalyticsRollupInterval time.Duration // 瀏覽事件匯總為每日統計的間隔
+
+	// 回收站
+	TrashRetentionDays int           // 刪除的文章與用戶保留天數，0 表示不自動清理
+	TrashPurgeInterval time.Duration // 自動清理的檢查間隔
+
+	// 導入
+	ImportPath    string // 上傳的導入文件在處理完成前的存放目錄
+	MaxImportSize int64  // 字節
+
+	// 各路由組 GET 響應的 Cache-Control 策略
+	CacheControlPublic  string // 無需登錄的公開路由，可被 CDN 緩存
+	CacheControlPrivate string // 需要登錄的路由
+	CacheControlAdmin   string // 管理員路由
+
+	// 查詢緩存
+	CacheBackend    string        // memory 或 none
+	CacheMaxEntries int           // 最大條目數
+	CacheTTL        time.Duration // 默認過期時間
 }
 
 var AppConfig *Config
@@ -35,12 +90,55 @@ func LoadConfig() {
 	AppConfig = &Config{
 		Port:       port,
 		GinMode:    getEnv("GIN_MODE", "debug"),
+		SiteURL:    strings.TrimRight(getEnv("SITE_URL", "http://localhost:8080"), "/"),
 		DBType:     getEnv("DB_TYPE", "sqlite"),
 		DBPath:     getEnv("DB_PATH", "./data/app.db"),
 		JWTSecret:  getEnv("JWT_SECRET", "default-secret-key"),
 		JWTExpires: getEnv("JWT_EXPIRES_IN", "24h"),
 		AdminEmail: getEnv("ADMIN_EMAIL", "admin@example.com"),
 		AdminPass:  getEnv("ADMIN_PASSWORD", "admin123"),
+
+		StorageBackend:   getEnv("STORAGE_BACKEND", "local"),
+		StoragePath:      getEnv("STORAGE_PATH", "./data/uploads"),
+		S3Endpoint:       getEnv("S3_ENDPOINT", "http://localhost:9000"),
+		S3Region:         getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:         getEnv("S3_BUCKET", "media"),
+		S3AccessKey:      getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:      getEnv("S3_SECRET_KEY", ""),
+		S3PathStyle:      getEnvBool("S3_PATH_STYLE", true),
+		MaxUploadSize:    getEnvInt64("MAX_UPLOAD_SIZE_MB", 10) << 20,
+		UserStorageQuota: getEnvInt64("USER_STORAGE_QUOTA_MB", 200) << 20,
+		SignedURLExpires: getEnvDuration("SIGNED_URL_EXPIRES", 15*time.Minute),
+
+		PreviewExpires:    getEnvDuration("PREVIEW_EXPIRES", 72*time.Hour),
+		PreviewMaxExpires: getEnvDuration("PREVIEW_MAX_EXPIRES", 30*24*time.Hour),
+
+		PostGrantExpires:      getEnvDuration("POST_GRANT_EXPIRES", time.Hour),
+		PostUnlockMaxAttempts: int(getEnvInt64("POST_UNLOCK_MAX_ATTEMPTS", 5)),
+		PostUnlockLockout:     getEnvDuration("POST_UNLOCK_LOCKOUT", 15*time.Minute),
+
+		RelatedPostsCount:    int(getEnvInt64("RELATED_POSTS_COUNT", 5)),
+		RelatedExcludeAuthor: getEnvBool("RELATED_EXCLUDE_AUTHOR", false),
+		RelatedIndexInterval: getEnvDuration("RELATED_INDEX_INTERVAL", time.Minute),
+
+		ViewFlushInterval: getEnvDuration("VIEW_FLUSH_INTERVAL", 10*time.Second),
+		ViewDedupWindow:   getEnvDuration("VIEW_DEDUP_WINDOW", 30*time.Minute),
+
+		AnalyticsRollupInterval: getEnvDuration("ANALYTICS_ROLLUP_INTERVAL", time.Hour),
+
+		TrashRetentionDays: int(getEnvInt64("TRASH_RETENTION_DAYS", 30)),
+		TrashPurgeInterval: getEnvDuration("TRASH_PURGE_INTERVAL", time.Hour),
+
+		ImportPath:    getEnv("IMPORT_PATH", "./data/imports"),
+		MaxImportSize: getEnvInt64("MAX_IMPORT_SIZE_MB", 512) << 20,
+
+		CacheControlPublic:  getEnv("CACHE_CONTROL_PUBLIC", "public, max-age=60, s-maxage=300"),
+		CacheControlPrivate: getEnv("CACHE_CONTROL_PRIVATE", "private, no-cache"),
+		CacheControlAdmin:   getEnv("CACHE_CONTROL_ADMIN", "private, no-cache"),
+
+		CacheBackend:    getEnv("CACHE_BACKEND", "memory"),
+		CacheMaxEntries: int(getEnvInt64("CACHE_MAX_ENTRIES", 10000)),
+		CacheTTL:        getEnvDuration("CACHE_TTL", 5*time.Minute),
 	}
 }
 
@@ -50,3 +148,24 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvInt64(key string, defaultValue int64) int64 {
+	if value, err := strconv.ParseInt(os.Getenv(key), 10, 64); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value, err := strconv.ParseBool(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,88 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"log"
+	"time"
+
+	"backend/config"
+)
+
+// Cache 鍵值緩存，值為序列化後的字節，便於替換為 Redis 等進程外實現
+type Cache interface {
+	// Name 後端名稱
+	Name() string
+	// Get 讀取緩存，不存在或已過期時返回 false
+	Get(key string) ([]byte, bool)
+	// Set 寫入緩存，ttl 為 0 時使用默認過期時間
+	Set(key string, value []byte, ttl time.Duration)
+	// Delete 刪除指定的鍵
+	Delete(keys ...string)
+	// DeletePrefix 刪除所有以 prefix 開頭的鍵
+	DeletePrefix(prefix string)
+	// Stats 命中統計
+	Stats() Stats
+}
+
+// Stats 緩存命中統計
+type Stats struct {
+	Backend   string  `json:"backend"`
+	Entries   int     `json:"entries"`
+	Capacity  int     `json:"capacity"`
+	Hits      uint64  `json:"hits"`
+	Misses    uint64  `json:"misses"`
+	HitRate   float64 `json:"hit_rate"`
+	Evictions uint64  `json:"evictions"` // 超出容量被淘汰的條目數
+	Expired   uint64  `json:"expired"`   // 過期被刪除的條目數
+}
+
+// 當前使用的緩存，未初始化時不緩存（命令行工具等場景）
+var Store Cache = Noop{}
+
+// 初始化緩存後端
+func InitCache() {
+	var err error
+	Store, err = New(config.AppConfig)
+	if err != nil {
+		log.Fatal("初始化緩存失敗:", err)
+	}
+	log.Printf("緩存後端: %s", Store.Name())
+}
+
+// New 根據配置創建緩存後端
+func New(cfg *config.Config) (Cache, error) {
+	switch cfg.CacheBackend {
+	case "memory":
+		return NewLRU(cfg.CacheMaxEntries, cfg.CacheTTL), nil
+	case "none":
+		return Noop{}, nil
+	default:
+		return nil, fmt.Errorf("不支持的緩存後端: %s", cfg.CacheBackend)
+	}
+}
+
+// GetObject 讀取緩存並用 gob 解碼到 v，每次調用都得到新的副本
+func GetObject(key string, v interface{}) bool {
+	data, ok := Store.Get(key)
+	if !ok {
+		return false
+	}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(v); err != nil {
+		// 結構變更後舊數據無法解碼，直接丟棄
+		Store.Delete(key)
+		return false
+	}
+	return true
+}
+
+// SetObject 用 gob 編碼 v 後寫入緩存
+func SetObject(key string, v interface{}, ttl time.Duration) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		log.Printf("緩存編碼失敗 %s: %v", key, err)
+		return
+	}
+	Store.Set(key, buf.Bytes(), ttl)
+}
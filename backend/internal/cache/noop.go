@@ -0,0 +1,24 @@
+package cache
+
+import "time"
+
+// Noop 不緩存任何數據，用於關閉緩存
+type Noop struct{}
+
+func (Noop) Name() string {
+	return "none"
+}
+
+func (Noop) Get(key string) ([]byte, bool) {
+	return nil, false
+}
+
+func (Noop) Set(key string, value []byte, ttl time.Duration) {}
+
+func (Noop) Delete(keys ...string) {}
+
+func (Noop) DeletePrefix(prefix string) {}
+
+func (Noop) Stats() Stats {
+	return Stats{Backend: "none"}
+}
@@ -28,7 +28,9 @@ func InitDB() {
 	}
 
 	// 連接數據庫
-	DB, err = gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+	// 後台任務與請求並發寫入：鎖被佔用時等待而不是立即失敗，事務開始即獲取寫鎖，避免讀鎖升級時衝突
+	dsn := dbPath + "?_busy_timeout=5000&_txlock=immediate"
+	DB, err = gorm.Open(sqlite.Open(dsn), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
 	})
 	if err != nil {
@@ -46,6 +48,10 @@ func InitDB() {
 
 // 自動遷移
 func autoMigrate() {
+	if err := migrateCommentUserID(); err != nil {
+		log.Fatal("遷移評論數據失敗:", err)
+	}
+
 	err := DB.AutoMigrate(
 		&models.User{},
 		&models.Post{},
@@ -53,13 +59,107 @@ func autoMigrate() {
 		&models.Category{},
 		&models.Setting{},
 		&models.Log{},
+		&models.PostStatusHistory{},
+		&models.PostSlugRedirect{},
+		&models.PostRender{},
+		&models.PostCollaborator{},
+		&models.PreviewToken{},
+		&models.Series{},
+		&models.SeriesPost{},
+		&models.Comment{},
+		&models.Reaction{},
+		&models.Bookmark{},
+		&models.Media{},
+		&models.PostViewEvent{},
+		&models.PostDailyStat{},
+		&models.PostDailyVisitor{},
+		&models.PostReferrerStat{},
+		&models.PostTerm{},
+		&models.PostTextIndex{},
+		&models.PostSimilarity{},
+		&models.ImportJob{},
 	)
 	if err != nil {
 		log.Fatal("數據庫遷移失敗:", err)
 	}
+
+	if err := migrateUnknownPostStatus(); err != nil {
+		log.Fatal("遷移文章狀態失敗:", err)
+	}
+
+	// SQLite 不支持為已有表新增 UNIQUE 欄位，唯一索引單獨建立
+	// 用戶名與郵箱只在未刪除的用戶中唯一，刪除後可被新帳號使用
+	indexes := []string{
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_posts_slug ON posts(slug)",
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_posts_external_id ON posts(external_id) WHERE external_id <> ''",
+		"DROP INDEX IF EXISTS idx_users_username",
+		"DROP INDEX IF EXISTS idx_users_email",
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username_active ON users(username) WHERE deleted_at IS NULL",
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_active ON users(email) WHERE deleted_at IS NULL",
+	}
+	for _, stmt := range indexes {
+		if err := DB.Exec(stmt).Error; err != nil {
+			log.Fatal("創建索引失敗:", err)
+		}
+	}
 	log.Println("數據庫遷移完成")
 }
 
+// 評論的 user_id 原為 NOT NULL，導入的訪客評論以 0 表示沒有對應用戶，改為可空並以 NULL 表示
+// AutoMigrate 不會移除 NOT NULL 約束，需要單獨修改欄位；SQLite 重建表時會丟失索引，
+// 因此在 AutoMigrate 之前執行，由其重新建立索引
+func migrateCommentUserID() error {
+	if !DB.Migrator().HasTable(&models.Comment{}) {
+		return nil
+	}
+	columns, err := DB.Migrator().ColumnTypes(&models.Comment{})
+	if err != nil {
+		return err
+	}
+	for _, column := range columns {
+		if nullable, ok := column.Nullable(); column.Name() == "user_id" && ok && !nullable {
+			if err := DB.Migrator().AlterColumn(&models.Comment{}, "UserID"); err != nil {
+				return err
+			}
+		}
+	}
+	return DB.Exec("UPDATE comments SET user_id = NULL WHERE user_id = 0").Error
+}
+
+// 舊數據中的文章狀態可能有拼寫錯誤（如 "publised"），不在狀態機中的文章無法變更狀態
+// 統一重置為草稿並記錄到狀態歷史，ActorID 為 0 表示系統操作
+func migrateUnknownPostStatus() error {
+	known := []string{models.PostStatusDraft, models.PostStatusInReview, models.PostStatusPublished, models.PostStatusArchived}
+	return DB.Transaction(func(tx *gorm.DB) error {
+		var posts []models.Post
+		if err := tx.Unscoped().Select("id", "status").
+			Where("status IS NULL OR status NOT IN ?", known).Find(&posts).Error; err != nil {
+			return err
+		}
+		for _, post := range posts {
+			if err := tx.Unscoped().Model(&models.Post{}).Where("id = ?", post.ID).UpdateColumns(map[string]interface{}{
+				"status":  models.PostStatusDraft,
+				"version": gorm.Expr("version + 1"),
+			}).Error; err != nil {
+				return err
+			}
+			history := models.PostStatusHistory{
+				PostID:     post.ID,
+				FromStatus: post.Status,
+				ToStatus:   models.PostStatusDraft,
+				Comment:    "數據遷移：未知狀態重置為草稿",
+			}
+			if err := tx.Create(&history).Error; err != nil {
+				return err
+			}
+		}
+		if len(posts) > 0 {
+			log.Printf("%d 篇文章的狀態無效，已重置為草稿", len(posts))
+		}
+		return nil
+	})
+}
+
 // 初始化數據
 func initData() {
 	// 檢查是否已有管理員用戶
@@ -93,6 +193,7 @@ func initData() {
 		{Key: "site_description", Value: "基於 Gin 的後台管理系統", Type: "string", Group: "basic"},
 		{Key: "posts_per_page", Value: "10", Type: "number", Group: "content"},
 		{Key: "allow_registration", Value: "true", Type: "boolean", Group: "user"},
+		{Key: "comment_moderation", Value: "true", Type: "boolean", Group: "content"},
 	}
 
 	for _, setting := range settings {
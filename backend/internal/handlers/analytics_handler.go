@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"backend/internal/services"
+	"backend/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 數據面板默認返回的文章排行數
+const defaultTopPosts = 10
+
+type AnalyticsHandler struct {
+	analyticsService    *services.AnalyticsService
+	postService         *services.PostService
+	collaboratorService *services.CollaboratorService
+}
+
+func NewAnalyticsHandler() *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService:    services.NewAnalyticsService(),
+		postService:         services.NewPostService(),
+		collaboratorService: services.NewCollaboratorService(),
+	}
+}
+
+// 解析 from/to 查詢參數
+func statsRange(c *gin.Context) (string, string, bool) {
+	from, to, err := services.ParseStatsRange(c.Query("from"), c.Query("to"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error()+"（格式 YYYY-MM-DD，最多 366 天）")
+		return "", "", false
+	}
+	return from, to, true
+}
+
+// 獲取文章瀏覽統計
+func (h *AnalyticsHandler) GetPostStats(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "無效的文章ID")
+		return
+	}
+
+	post, err := h.postService.GetPostByID(uint(id))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "文章不存在")
+		return
+	}
+
+	// 檢查權限：作者、共同作者和管理員可以查看統計，其他用戶返回 404，不暴露文章是否存在
+	perm, err := h.collaboratorService.GetPermission(post, currentActor(c))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "檢查文章權限失敗")
+		return
+	}
+	if !perm.ViewStats {
+		utils.ErrorResponse(c, http.StatusNotFound, "文章不存在")
+		return
+	}
+
+	from, to, ok := statsRange(c)
+	if !ok {
+		return
+	}
+
+	stats, err := h.analyticsService.GetPostStats(post.ID, from, to)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取統計數據失敗")
+		return
+	}
+
+	utils.SuccessResponse(c, stats)
+}
+
+// 獲取作者數據面板，管理員可通過 author_id 查看其他作者
+func (h *AnalyticsHandler) GetAuthorStats(c *gin.Context) {
+	actor := currentActor(c)
+	authorID := actor.UserID
+	if idStr := c.Query("author_id"); idStr != "" {
+		id, err := strconv.ParseUint(idStr, 10, 32)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "無效的作者ID")
+			return
+		}
+		if uint(id) != actor.UserID && !actor.IsAdmin() {
+			utils.ErrorResponse(c, http.StatusForbidden, "沒有權限查看其他作者的統計")
+			return
+		}
+		authorID = uint(id)
+	}
+
+	from, to, ok := statsRange(c)
+	if !ok {
+		return
+	}
+
+	top := defaultTopPosts
+	if t, err := strconv.Atoi(c.Query("top")); err == nil && t > 0 && t <= 100 {
+		top = t
+	}
+
+	stats, err := h.analyticsService.GetAuthorStats(authorID, from, to, top)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取統計數據失敗")
+		return
+	}
+
+	utils.SuccessResponse(c, stats)
+}
@@ -1,21 +1,26 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
+	"backend/config"
 	"backend/internal/services"
+	"backend/pkg/imaging"
 	"backend/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
 type AuthHandler struct {
-	userService *services.UserService
+	userService   *services.UserService
+	avatarService *services.AvatarService
 }
 
 func NewAuthHandler() *AuthHandler {
 	return &AuthHandler{
-		userService: services.NewUserService(),
+		userService:   services.NewUserService(),
+		avatarService: services.NewAvatarService(),
 	}
 }
 
@@ -108,13 +113,16 @@ func (h *AuthHandler) Profile(c *gin.Context) {
 	// 不返回密碼
 	user.Password = ""
 
-	utils.SuccessResponse(c, user)
+	utils.ResourceResponse(c, user, user.Version, user.UpdatedAt)
 }
 
 // 更新個人資料
 type UpdateProfileRequest struct {
 	Username string `json:"username"`
 	Avatar   string `json:"avatar"`
+
+	// 版本號，也可通過 If-Match 請求頭提供
+	Version *uint `json:"version"`
 }
 
 // 更新個人資料
@@ -131,6 +139,11 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
+	version, ok := requestVersion(c, req.Version, true)
+	if !ok {
+		return
+	}
+
 	updates := make(map[string]interface{})
 	if req.Username != "" {
 		updates["username"] = req.Username
@@ -139,8 +152,15 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 		updates["avatar"] = req.Avatar
 	}
 
-	user, err := h.userService.UpdateUser(userID.(uint), updates)
+	user, err := h.userService.UpdateUser(userID.(uint), version, updates)
 	if err != nil {
+		if errors.Is(err, services.ErrVersionConflict) {
+			if current, err := h.userService.GetUserByID(userID.(uint)); err == nil {
+				current.Password = ""
+				respondVersionConflict(c, current, current.Version)
+				return
+			}
+		}
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -148,6 +168,49 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 	// 不返回密碼
 	user.Password = ""
 
+	utils.ResourceResponse(c, user, user.Version, user.UpdatedAt)
+}
+
+// 上傳頭像
+func (h *AuthHandler) UploadAvatar(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "未登錄")
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, config.AppConfig.MaxUploadSize+1<<20)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "請選擇要上傳的頭像")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "讀取上傳文件失敗")
+		return
+	}
+	defer file.Close()
+
+	user, err := h.avatarService.UploadAvatar(c.Request.Context(), userID.(uint), file)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrFileTooLarge):
+			utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, err.Error())
+		case errors.Is(err, services.ErrUnsupportedMediaType), errors.Is(err, imaging.ErrUnsupportedFormat):
+			utils.ErrorResponse(c, http.StatusUnsupportedMediaType, imaging.ErrUnsupportedFormat.Error())
+		case errors.Is(err, imaging.ErrImageTooLarge):
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "上傳頭像失敗: "+err.Error())
+		}
+		return
+	}
+
+	// 不返回密碼
+	user.Password = ""
+
 	utils.SuccessResponse(c, user)
 }
 
@@ -189,7 +252,7 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 		"password": req.NewPassword,
 	}
 
-	_, err = h.userService.UpdateUser(userID.(uint), updates)
+	_, err = h.userService.UpdateUser(userID.(uint), 0, updates)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "密碼修改失敗")
 		return
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+
+	"backend/internal/services"
+	"backend/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BookmarkHandler struct {
+	bookmarkService     *services.BookmarkService
+	postService         *services.PostService
+	collaboratorService *services.CollaboratorService
+}
+
+func NewBookmarkHandler() *BookmarkHandler {
+	return &BookmarkHandler{
+		bookmarkService:     services.NewBookmarkService(),
+		postService:         services.NewPostService(),
+		collaboratorService: services.NewCollaboratorService(),
+	}
+}
+
+// 收藏文章，只能收藏有權查看的文章
+func (h *BookmarkHandler) AddBookmark(c *gin.Context) {
+	post, ok := readablePost(c, h.postService, h.collaboratorService)
+	if !ok {
+		return
+	}
+
+	if err := h.bookmarkService.AddBookmark(post.ID, currentActor(c).UserID); err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "文章不存在")
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{"bookmarked": true})
+}
+
+// 取消收藏
+func (h *BookmarkHandler) RemoveBookmark(c *gin.Context) {
+	post, ok := readablePost(c, h.postService, h.collaboratorService)
+	if !ok {
+		return
+	}
+
+	if err := h.bookmarkService.RemoveBookmark(post.ID, currentActor(c).UserID); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "取消收藏失敗")
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{"bookmarked": false})
+}
+
+// 獲取我的收藏
+func (h *BookmarkHandler) GetBookmarks(c *gin.Context) {
+	page, limit := utils.GetPaginationParams(c)
+
+	bookmarks, total, err := h.bookmarkService.GetBookmarks(currentActor(c), page, limit)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取收藏列表失敗")
+		return
+	}
+
+	utils.PaginatedSuccessResponse(c, bookmarks, page, limit, total)
+}
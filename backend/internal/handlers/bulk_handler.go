@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"backend/internal/services"
+	"backend/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 批量操作篩選表達式允許的字段，拼寫錯誤的字段不能被靜默忽略
+var (
+	bulkPostFilterKeys = []string{"status", "author_id", "category_id", "tag_ids", "tag_match", "title_prefix",
+		"created_from", "created_to", "updated_from", "updated_to"}
+	bulkUserFilterKeys = []string{"role", "status", "username_prefix", "created_from", "created_to"}
+)
+
+type BulkHandler struct {
+	bulkService *services.BulkService
+}
+
+func NewBulkHandler() *BulkHandler {
+	return &BulkHandler{
+		bulkService: services.NewBulkService(),
+	}
+}
+
+// 解析篩選表達式，格式與列表查詢參數相同，如 "status=draft&author_id=3"
+func parseBulkFilter(expr string, allowed []string) (url.Values, error) {
+	values, err := url.ParseQuery(expr)
+	if err != nil {
+		return nil, fmt.Errorf("無效的篩選表達式: %v", err)
+	}
+	for key := range values {
+		found := false
+		for _, k := range allowed {
+			if k == key {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("不支持的篩選字段: %q", key)
+		}
+	}
+	return values, nil
+}
+
+// 批量操作錯誤對應的狀態碼
+func bulkErrorCode(err error) int {
+	switch {
+	case errors.Is(err, services.ErrInvalidBulkAction),
+		errors.Is(err, services.ErrBulkTargetRequired),
+		errors.Is(err, services.ErrBulkTooManyItems),
+		errors.Is(err, services.ErrBulkTagsRequired),
+		errors.Is(err, services.ErrTagNotFound),
+		errors.Is(err, services.ErrAuthorNotFound),
+		errors.Is(err, services.ErrInvalidRole):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// 文章批量操作
+type BulkPostsRequest struct {
+	Action   string `json:"action" binding:"required"`
+	IDs      []uint `json:"ids"`
+	Filter   string `json:"filter"`
+	DryRun   bool   `json:"dry_run"`
+	Atomic   bool   `json:"atomic"`
+	TagIDs   []uint `json:"tag_ids"`
+	AuthorID uint   `json:"author_id"`
+}
+
+func (h *BulkHandler) BulkPosts(c *gin.Context) {
+	var req BulkPostsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "請求參數錯誤: "+err.Error())
+		return
+	}
+
+	bulk := services.BulkPostRequest{
+		Action:   req.Action,
+		IDs:      req.IDs,
+		DryRun:   req.DryRun,
+		Atomic:   req.Atomic,
+		TagIDs:   req.TagIDs,
+		AuthorID: req.AuthorID,
+	}
+	if req.Filter != "" {
+		values, err := parseBulkFilter(req.Filter, bulkPostFilterKeys)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		filter, err := parsePostFilter(values)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		bulk.Filter = &filter
+	}
+
+	report, err := h.bulkService.BulkPosts(bulk, currentActor(c))
+	if err != nil {
+		code := bulkErrorCode(err)
+		if code == http.StatusInternalServerError {
+			utils.ErrorResponse(c, code, "批量操作失敗")
+			return
+		}
+		utils.ErrorResponse(c, code, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, report)
+}
+
+// 用戶批量操作
+type BulkUsersRequest struct {
+	Action string `json:"action" binding:"required"`
+	IDs    []uint `json:"ids"`
+	Filter string `json:"filter"`
+	DryRun bool   `json:"dry_run"`
+	Atomic bool   `json:"atomic"`
+	Role   string `json:"role"`
+}
+
+func (h *BulkHandler) BulkUsers(c *gin.Context) {
+	var req BulkUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "請求參數錯誤: "+err.Error())
+		return
+	}
+
+	bulk := services.BulkUserRequest{
+		Action: req.Action,
+		IDs:    req.IDs,
+		DryRun: req.DryRun,
+		Atomic: req.Atomic,
+		Role:   req.Role,
+	}
+	if req.Filter != "" {
+		values, err := parseBulkFilter(req.Filter, bulkUserFilterKeys)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		filter, err := parseUserFilter(values)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		bulk.Filter = &filter
+	}
+
+	report, err := h.bulkService.BulkUsers(bulk, currentActor(c))
+	if err != nil {
+		code := bulkErrorCode(err)
+		if code == http.StatusInternalServerError {
+			utils.ErrorResponse(c, code, "批量操作失敗")
+			return
+		}
+		utils.ErrorResponse(c, code, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, report)
+}
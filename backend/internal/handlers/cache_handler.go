@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"backend/internal/cache"
+	"backend/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CacheHandler struct {
+	store cache.Cache
+}
+
+func NewCacheHandler() *CacheHandler {
+	return &CacheHandler{
+		store: cache.Store,
+	}
+}
+
+// 獲取查詢緩存的命中統計
+func (h *CacheHandler) GetStats(c *gin.Context) {
+	utils.SuccessResponse(c, h.store.Stats())
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"backend/internal/models"
+	"backend/internal/services"
+	"backend/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CollaboratorHandler struct {
+	postService         *services.PostService
+	collaboratorService *services.CollaboratorService
+}
+
+func NewCollaboratorHandler() *CollaboratorHandler {
+	return &CollaboratorHandler{
+		postService:         services.NewPostService(),
+		collaboratorService: services.NewCollaboratorService(),
+	}
+}
+
+// 獲取文章並檢查權限，manage 為 true 時需要管理協作者的權限，否則需要查看權限
+// 返回 false 時已寫入錯誤響應
+func (h *CollaboratorHandler) getPost(c *gin.Context, manage bool) (*models.Post, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "無效的文章ID")
+		return nil, false
+	}
+
+	post, err := h.postService.GetPostByID(uint(id))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "文章不存在")
+		return nil, false
+	}
+
+	perm, err := h.collaboratorService.GetPermission(post, currentActor(c))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "檢查文章權限失敗")
+		return nil, false
+	}
+	if (manage && !perm.Manage) || (!manage && !perm.Read) {
+		utils.ErrorResponse(c, http.StatusForbidden, "沒有權限管理此文章的協作者")
+		return nil, false
+	}
+	return post, true
+}
+
+// 解析路徑中的用戶ID
+func collaboratorUserID(c *gin.Context) (uint, bool) {
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "無效的用戶ID")
+		return 0, false
+	}
+	return uint(userID), true
+}
+
+// 獲取文章協作者
+func (h *CollaboratorHandler) GetCollaborators(c *gin.Context) {
+	post, ok := h.getPost(c, false)
+	if !ok {
+		return
+	}
+
+	collaborators, err := h.collaboratorService.GetCollaborators(post.ID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取協作者失敗")
+		return
+	}
+
+	utils.SuccessResponse(c, collaborators)
+}
+
+// 設置協作者請求結構
+type SetCollaboratorRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// 添加協作者或變更其角色
+func (h *CollaboratorHandler) SetCollaborator(c *gin.Context) {
+	post, ok := h.getPost(c, true)
+	if !ok {
+		return
+	}
+	userID, ok := collaboratorUserID(c)
+	if !ok {
+		return
+	}
+
+	var req SetCollaboratorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "請求參數錯誤: "+err.Error())
+		return
+	}
+
+	collaborator, err := h.collaboratorService.SetCollaborator(post, userID, req.Role)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidCollaboratorRole), errors.Is(err, services.ErrCollaboratorIsAuthor):
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, services.ErrCollaboratorUserMissing):
+			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "設置協作者失敗")
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, collaborator)
+}
+
+// 移除協作者，協作者也可以移除自己
+func (h *CollaboratorHandler) RemoveCollaborator(c *gin.Context) {
+	userID, ok := collaboratorUserID(c)
+	if !ok {
+		return
+	}
+	post, ok := h.getPost(c, userID != currentActor(c).UserID)
+	if !ok {
+		return
+	}
+
+	if err := h.collaboratorService.RemoveCollaborator(post.ID, userID); err != nil {
+		if errors.Is(err, services.ErrCollaboratorNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "移除協作者失敗")
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{"message": "協作者已移除"})
+}
@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"backend/internal/models"
+	"backend/internal/services"
+	"backend/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CommentHandler struct {
+	commentService      *services.CommentService
+	postService         *services.PostService
+	collaboratorService *services.CollaboratorService
+}
+
+func NewCommentHandler() *CommentHandler {
+	return &CommentHandler{
+		commentService:      services.NewCommentService(),
+		postService:         services.NewPostService(),
+		collaboratorService: services.NewCollaboratorService(),
+	}
+}
+
+// 發表評論請求結構
+type CreateCommentRequest struct {
+	Content  string `json:"content" binding:"required,max=5000"`
+	ParentID *uint  `json:"parent_id"`
+}
+
+// 發表評論
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	idStr := c.Param("id")
+	postID, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "無效的文章ID")
+		return
+	}
+
+	var req CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "請求參數錯誤: "+err.Error())
+		return
+	}
+	req.Content = strings.TrimSpace(req.Content)
+	if req.Content == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "評論內容不能為空")
+		return
+	}
+
+	post, err := h.postService.GetPostByID(uint(postID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "文章不存在")
+		return
+	}
+	if !checkPostAccess(c, h.collaboratorService, post) {
+		return
+	}
+
+	comment, err := h.commentService.CreateComment(uint(postID), req.ParentID, req.Content, currentActor(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrCommentsClosed):
+			utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+		case errors.Is(err, services.ErrInvalidParentComment), errors.Is(err, services.ErrCommentTooDeep):
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusNotFound, "文章不存在")
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, comment)
+}
+
+// 獲取文章評論（嵌套樹）
+func (h *CommentHandler) GetComments(c *gin.Context) {
+	idStr := c.Param("id")
+	postID, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "無效的文章ID")
+		return
+	}
+
+	post, err := h.postService.GetPostByID(uint(postID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "文章不存在")
+		return
+	}
+	if !checkPostAccess(c, h.collaboratorService, post) {
+		return
+	}
+
+	page, limit := utils.GetPaginationParams(c)
+	comments, total, err := h.commentService.GetCommentTree(uint(postID), page, limit, currentActor(c))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取評論失敗")
+		return
+	}
+
+	utils.PaginatedSuccessResponse(c, comments, page, limit, total)
+}
+
+// 編輯評論請求結構
+type UpdateCommentRequest struct {
+	Content string `json:"content" binding:"required,max=5000"`
+}
+
+// 編輯評論
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "無效的評論ID")
+		return
+	}
+
+	var req UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "請求參數錯誤: "+err.Error())
+		return
+	}
+	req.Content = strings.TrimSpace(req.Content)
+	if req.Content == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "評論內容不能為空")
+		return
+	}
+
+	existingComment, err := h.commentService.GetCommentByID(uint(id))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "評論不存在")
+		return
+	}
+
+	// 檢查權限：只有評論者本人可以編輯
+	actor := currentActor(c)
+	if !isCommentAuthor(existingComment, actor) {
+		utils.ErrorResponse(c, http.StatusForbidden, "沒有權限編輯此評論")
+		return
+	}
+
+	comment, err := h.commentService.UpdateComment(uint(id), req.Content, actor)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "編輯評論失敗")
+		return
+	}
+
+	utils.SuccessResponse(c, comment)
+}
+
+// 是否為評論者本人，導入的訪客評論不屬於任何用戶
+func isCommentAuthor(comment *models.Comment, actor services.Actor) bool {
+	return comment.UserID != nil && *comment.UserID == actor.UserID
+}
+
+// 刪除評論
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "無效的評論ID")
+		return
+	}
+
+	existingComment, err := h.commentService.GetCommentByID(uint(id))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "評論不存在")
+		return
+	}
+
+	// 檢查權限：評論者本人和管理員可以刪除
+	actor := currentActor(c)
+	if !actor.IsAdmin() && !isCommentAuthor(existingComment, actor) {
+		utils.ErrorResponse(c, http.StatusForbidden, "沒有權限刪除此評論")
+		return
+	}
+
+	if err := h.commentService.DeleteComment(uint(id)); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "刪除評論失敗")
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{"message": "評論刪除成功"})
+}
+
+// 開關評論請求結構
+type SetCommentsClosedRequest struct {
+	Closed bool `json:"closed"`
+}
+
+// 開啟或關閉文章評論
+func (h *CommentHandler) SetCommentsClosed(c *gin.Context) {
+	idStr := c.Param("id")
+	postID, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "無效的文章ID")
+		return
+	}
+
+	var req SetCommentsClosedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "請求參數錯誤: "+err.Error())
+		return
+	}
+
+	existingPost, err := h.postService.GetPostByID(uint(postID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "文章不存在")
+		return
+	}
+
+	// 檢查權限：只有作者和管理員可以開關評論
+	actor := currentActor(c)
+	if !actor.IsAdmin() && existingPost.AuthorID != actor.UserID {
+		utils.ErrorResponse(c, http.StatusForbidden, "沒有權限修改此文章評論設置")
+		return
+	}
+
+	if err := h.commentService.SetCommentsClosed(uint(postID), req.Closed); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "修改評論設置失敗")
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{"comments_closed": req.Closed})
+}
+
+// 獲取評論審核隊列
+func (h *CommentHandler) GetModerationQueue(c *gin.Context) {
+	page, limit := utils.GetPaginationParams(c)
+	status := c.DefaultQuery("status", models.CommentStatusPending)
+	if !services.IsValidCommentStatus(status) {
+		utils.ErrorResponse(c, http.StatusBadRequest, services.ErrInvalidCommentStatus.Error())
+		return
+	}
+
+	comments, total, err := h.commentService.GetModerationQueue(page, limit, status)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取審核隊列失敗")
+		return
+	}
+
+	utils.PaginatedSuccessResponse(c, comments, page, limit, total)
+}
+
+// 審核評論請求結構
+type ModerateCommentRequest struct {
+	Status string `json:"status" binding:"required"`
+}
+
+// 審核評論
+func (h *CommentHandler) ModerateComment(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "無效的評論ID")
+		return
+	}
+
+	var req ModerateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "請求參數錯誤: "+err.Error())
+		return
+	}
+
+	comment, err := h.commentService.SetCommentStatus(uint(id), req.Status)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCommentStatus) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusNotFound, "評論不存在")
+		return
+	}
+
+	utils.SuccessResponse(c, comment)
+}
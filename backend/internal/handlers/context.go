@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"backend/internal/models"
+	"backend/internal/services"
+	"backend/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 從上下文獲取當前操作者
+func currentActor(c *gin.Context) services.Actor {
+	var actor services.Actor
+	if id, exists := c.Get("user_id"); exists {
+		actor.UserID, _ = id.(uint)
+	}
+	if role, exists := c.Get("role"); exists {
+		actor.Role, _ = role.(string)
+	}
+	return actor
+}
+
+// 密碼保護文章的訪問令牌：X-Post-Grant 請求頭或 grant 參數
+func postGrant(c *gin.Context) string {
+	if grant := c.GetHeader("X-Post-Grant"); grant != "" {
+		return grant
+	}
+	return c.Query("grant")
+}
+
+// 檢查當前用戶能否查看文章，返回 false 時已寫入錯誤響應
+// 作者、協作者與審核者可以查看全部文章，其他用戶只能查看可見範圍內的已發布文章
+// 未解鎖的密碼保護文章返回 403 以便前端提示輸入密碼，其他無權查看的文章返回 404
+func checkPostAccess(c *gin.Context, collaboratorService *services.CollaboratorService, post *models.Post) bool {
+	published := post.Status == models.PostStatusPublished
+	if published && services.CanViewPost(post, currentActor(c), postGrant(c)) {
+		return true
+	}
+
+	perm, err := collaboratorService.GetPermission(post, currentActor(c))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "檢查文章權限失敗")
+		return false
+	}
+	if perm.Read {
+		return true
+	}
+
+	if published && post.Visibility == models.PostVisibilityPassword {
+		c.JSON(http.StatusForbidden, utils.Response{
+			Code:    http.StatusForbidden,
+			Message: "此文章受密碼保護",
+			Data:    gin.H{"visibility": post.Visibility},
+		})
+		return false
+	}
+	utils.ErrorResponse(c, http.StatusNotFound, "文章不存在")
+	return false
+}
+
+// 獲取路徑參數 id 對應的文章並檢查當前用戶能否查看，返回 false 時已寫入錯誤響應
+func readablePost(c *gin.Context, postService *services.PostService, collaboratorService *services.CollaboratorService) (*models.Post, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "無效的文章ID")
+		return nil, false
+	}
+
+	post, err := postService.GetPostByID(uint(id))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "文章不存在")
+		return nil, false
+	}
+	if !checkPostAccess(c, collaboratorService, post) {
+		return nil, false
+	}
+	return post, true
+}
+
+// 訪客標識：登錄用戶使用用戶ID，匿名訪客使用 IP 與 User-Agent 的哈希
+func viewerID(c *gin.Context) string {
+	if userID := currentActor(c).UserID; userID != 0 {
+		return "u:" + strconv.FormatUint(uint64(userID), 10)
+	}
+	sum := sha256.Sum256([]byte(c.ClientIP() + "|" + c.Request.UserAgent()))
+	return "a:" + hex.EncodeToString(sum[:16])
+}
+
+// 瀏覽來源：前端可通過 ref 參數傳入 document.referrer，否則使用 Referer 頭
+func viewReferrer(c *gin.Context) string {
+	if ref := c.Query("ref"); ref != "" {
+		return ref
+	}
+	return c.Request.Referer()
+}
+
+// 從 If-Match 請求頭或請求體的 version 字段獲取客戶端持有的版本號，If-Match 優先
+// 都未提供時 required 為 true 則返回 428；If-Match 為 * 時返回 0，表示不校驗版本
+// 返回 false 時已寫入錯誤響應
+func requestVersion(c *gin.Context, body *uint, required bool) (uint, bool) {
+	version, ok, err := utils.ParseIfMatch(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return 0, false
+	}
+	if ok {
+		return version, true
+	}
+	if body != nil && *body != 0 {
+		return *body, true
+	}
+	if required {
+		utils.ErrorResponse(c, http.StatusPreconditionRequired, "請通過 If-Match 請求頭或 version 字段提供版本號")
+		return 0, false
+	}
+	return 0, true
+}
+
+// 版本衝突時返回 412 與服務器上的當前數據，ETag 為當前版本
+func respondVersionConflict(c *gin.Context, current interface{}, version uint) {
+	body, _ := json.Marshal(current)
+	c.Header("ETag", utils.ResourceETag(version, body))
+	c.JSON(http.StatusPreconditionFailed, utils.Response{
+		Code:    http.StatusPreconditionFailed,
+		Message: services.ErrVersionConflict.Error(),
+		Data:    current,
+	})
+}
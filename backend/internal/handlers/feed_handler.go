@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"backend/config"
+	"backend/internal/models"
+	"backend/internal/services"
+	"backend/pkg/feed"
+	"backend/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 訂閱源格式
+const (
+	FeedFormatRSS  = "rss"
+	FeedFormatAtom = "atom"
+	FeedFormatJSON = "json"
+)
+
+var feedContentTypes = map[string]string{
+	FeedFormatRSS:  "application/rss+xml; charset=utf-8",
+	FeedFormatAtom: "application/atom+xml; charset=utf-8",
+	FeedFormatJSON: "application/feed+json; charset=utf-8",
+}
+
+type FeedHandler struct {
+	feedService   *services.FeedService
+	renderService *services.RenderService
+}
+
+func NewFeedHandler() *FeedHandler {
+	return &FeedHandler{
+		feedService:   services.NewFeedService(),
+		renderService: services.NewRenderService(),
+	}
+}
+
+// SiteFeed 全站訂閱源
+func (h *FeedHandler) SiteFeed(format string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name, description := h.feedService.SiteInfo()
+		h.respondFeed(c, format, services.FeedFilter{}, name, description, "/feed."+format)
+	}
+}
+
+// AuthorFeed 作者訂閱源
+func (h *FeedHandler) AuthorFeed(format string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		author, err := h.feedService.GetAuthorByUsername(c.Param("username"))
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusNotFound, "用戶不存在")
+			return
+		}
+
+		name, _ := h.feedService.SiteInfo()
+		h.respondFeed(c, format, services.FeedFilter{AuthorID: author.ID},
+			author.Username+" - "+name, author.Username+" 發布的文章",
+			"/authors/"+url.PathEscape(author.Username)+"/feed."+format)
+	}
+}
+
+// TagFeed 標籤訂閱源
+func (h *FeedHandler) TagFeed(format string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tag, err := h.feedService.GetTagByName(c.Param("tag"))
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusNotFound, "標籤不存在")
+			return
+		}
+
+		name, _ := h.feedService.SiteInfo()
+		h.respondFeed(c, format, services.FeedFilter{TagID: tag.ID},
+			tag.Name+" - "+name, "標籤「"+tag.Name+"」下的文章",
+			"/tags/"+url.PathEscape(tag.Name)+"/feed."+format)
+	}
+}
+
+// 生成並輸出訂閱源，支持條件請求
+func (h *FeedHandler) respondFeed(c *gin.Context, format string, filter services.FeedFilter, title, description, path string) {
+	limit := h.feedService.DefaultLimit()
+	if l := c.Query("limit"); l != "" {
+		parsed, err := strconv.Atoi(l)
+		if err != nil || parsed < 1 || parsed > services.MaxFeedItems {
+			utils.ErrorResponse(c, http.StatusBadRequest, "limit 必須在 1 到 100 之間")
+			return
+		}
+		limit = parsed
+	}
+
+	posts, err := h.feedService.GetFeedPosts(filter, limit)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取文章列表失敗")
+		return
+	}
+	lastModified, err := h.feedService.LastModified(filter)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取文章列表失敗")
+		return
+	}
+
+	site := config.AppConfig.SiteURL
+	f := &feed.Feed{
+		Title:       title,
+		Description: description,
+		Link:        site + "/",
+		FeedURL:     site + path,
+		Language:    "zh-TW",
+	}
+	if len(c.Request.URL.RawQuery) > 0 {
+		f.FeedURL += "?" + c.Request.URL.RawQuery
+	}
+
+	for i := range posts {
+		item, err := h.feedItem(&posts[i])
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "渲染文章失敗")
+			return
+		}
+		f.Items = append(f.Items, item)
+	}
+
+	// 沒有文章時不提供 Last-Modified
+	if len(posts) == 0 {
+		lastModified = time.Time{}
+	}
+	f.Updated = lastModified
+	if f.Updated.IsZero() {
+		f.Updated = time.Now()
+	}
+
+	var body []byte
+	switch format {
+	case FeedFormatRSS:
+		body, err = f.RSS()
+	case FeedFormatAtom:
+		body, err = f.Atom()
+	default:
+		body, err = f.JSON()
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "生成訂閱源失敗")
+		return
+	}
+
+	// 訂閱源是文章列表，使用弱 ETag
+	etag := ""
+	if !lastModified.IsZero() {
+		etag = utils.WeakETag(body)
+	}
+	if utils.CheckNotModified(c, etag, lastModified) {
+		return
+	}
+	c.Data(http.StatusOK, feedContentTypes[format], body)
+}
+
+// 文章轉為訂閱源條目，正文使用渲染緩存
+func (h *FeedHandler) feedItem(post *models.Post) (feed.Item, error) {
+	rendered, err := h.renderService.GetRendered(post)
+	if err != nil {
+		return feed.Item{}, err
+	}
+
+	link := services.PostURL(post)
+	item := feed.Item{
+		ID:          services.PostFeedID(post),
+		Title:       post.Title,
+		Link:        link,
+		Summary:     post.Summary,
+		ContentHTML: rendered.HTML,
+		Published:   services.PostPublishedTime(post),
+		Updated:     post.UpdatedAt,
+	}
+	if post.Author.ID != 0 {
+		item.Author = post.Author.Username
+	}
+	for _, tag := range post.Tags {
+		item.Tags = append(item.Tags, tag.Name)
+	}
+	return item, nil
+}
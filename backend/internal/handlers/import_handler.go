@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	"backend/config"
+	"backend/internal/services"
+	"backend/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImportHandler struct {
+	imports *services.ImportRunner
+}
+
+func NewImportHandler() *ImportHandler {
+	return &ImportHandler{
+		imports: services.Imports,
+	}
+}
+
+// 上傳 WordPress 導出文件（WXR）並創建後台導入任務
+// 文件直接從請求體流式寫入磁盤，不經過內存緩存
+func (h *ImportHandler) ImportWordPress(c *gin.Context) {
+	// 限制請求體大小，預留表單字段的空間
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, config.AppConfig.MaxImportSize+1<<20)
+
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "請使用 multipart/form-data 上傳文件")
+		return
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "讀取上傳文件失敗")
+			return
+		}
+		if part.FormName() != "file" || part.FileName() == "" {
+			part.Close()
+			continue
+		}
+
+		job, err := h.imports.StartWordPressImport(currentActor(c).UserID, filepath.Base(part.FileName()), part)
+		part.Close()
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			switch {
+			case errors.Is(err, services.ErrImportTooLarge), errors.As(err, &maxBytesErr):
+				utils.ErrorResponse(c, http.StatusRequestEntityTooLarge,
+					fmt.Sprintf("文件超過 %d MB", config.AppConfig.MaxImportSize>>20))
+			case errors.Is(err, services.ErrImportEmpty):
+				utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			default:
+				utils.ErrorResponse(c, http.StatusInternalServerError, "保存導入文件失敗")
+			}
+			return
+		}
+
+		c.JSON(http.StatusAccepted, utils.Response{
+			Code:    http.StatusAccepted,
+			Message: "導入任務已創建",
+			Data:    job,
+		})
+		return
+	}
+
+	utils.ErrorResponse(c, http.StatusBadRequest, "請選擇要上傳的文件")
+}
+
+// 獲取導入任務列表
+func (h *ImportHandler) GetJobs(c *gin.Context) {
+	page, limit := utils.GetPaginationParams(c)
+
+	jobs, total, err := h.imports.GetJobs(page, limit)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取導入任務失敗")
+		return
+	}
+
+	utils.PaginatedSuccessResponse(c, jobs, page, limit, total)
+}
+
+// 獲取導入任務的進度與報告
+func (h *ImportHandler) GetJob(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "無效的任務ID")
+		return
+	}
+
+	job, err := h.imports.GetJob(uint(id))
+	if err != nil {
+		if errors.Is(err, services.ErrImportNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取導入任務失敗")
+		return
+	}
+
+	utils.SuccessResponse(c, job)
+}
@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"backend/config"
+	"backend/internal/models"
+	"backend/internal/services"
+	"backend/internal/storage"
+	"backend/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MediaHandler struct {
+	mediaService *services.MediaService
+}
+
+func NewMediaHandler() *MediaHandler {
+	return &MediaHandler{
+		mediaService: services.NewMediaService(),
+	}
+}
+
+// 上傳文件
+func (h *MediaHandler) Upload(c *gin.Context) {
+	// 限制請求體大小，預留表單字段的空間
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, config.AppConfig.MaxUploadSize+1<<20)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "請選擇要上傳的文件")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "讀取上傳文件失敗")
+		return
+	}
+	defer file.Close()
+
+	media, err := h.mediaService.Upload(c.Request.Context(), currentActor(c).UserID, fileHeader.Filename, file, fileHeader.Size)
+	if err != nil {
+		h.uploadError(c, err)
+		return
+	}
+
+	utils.SuccessResponse(c, media)
+}
+
+// 上傳錯誤對應的響應
+func (h *MediaHandler) uploadError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrFileTooLarge):
+		utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, fmt.Sprintf("%s（最大 %d MB）", err.Error(), config.AppConfig.MaxUploadSize>>20))
+	case errors.Is(err, services.ErrUnsupportedMediaType):
+		utils.ErrorResponse(c, http.StatusUnsupportedMediaType, err.Error())
+	case errors.Is(err, services.ErrQuotaExceeded):
+		utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "上傳文件失敗: "+err.Error())
+	}
+}
+
+// 獲取媒體列表，管理員可查看全部
+func (h *MediaHandler) GetMediaList(c *gin.Context) {
+	page, limit := utils.GetPaginationParams(c)
+
+	actor := currentActor(c)
+	userID := actor.UserID
+	if actor.IsAdmin() && c.Query("all") == "true" {
+		userID = 0
+	}
+
+	media, total, err := h.mediaService.GetMediaList(page, limit, userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取文件列表失敗")
+		return
+	}
+
+	utils.PaginatedSuccessResponse(c, media, page, limit, total)
+}
+
+// 獲取存儲用量
+func (h *MediaHandler) GetUsage(c *gin.Context) {
+	used, err := h.mediaService.GetUsage(currentActor(c).UserID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取存儲用量失敗")
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{
+		"used":  used,
+		"quota": config.AppConfig.UserStorageQuota,
+	})
+}
+
+// 獲取有權限訪問的媒體
+func (h *MediaHandler) getOwnedMedia(c *gin.Context) (*models.Media, bool) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "無效的文件ID")
+		return nil, false
+	}
+
+	media, err := h.mediaService.GetMediaByID(uint(id))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "文件不存在")
+		return nil, false
+	}
+
+	// 檢查權限：只有上傳者和管理員可以訪問
+	actor := currentActor(c)
+	if !actor.IsAdmin() && media.UserID != actor.UserID {
+		utils.ErrorResponse(c, http.StatusForbidden, "沒有權限訪問此文件")
+		return nil, false
+	}
+
+	return media, true
+}
+
+// 獲取媒體詳情
+func (h *MediaHandler) GetMedia(c *gin.Context) {
+	media, ok := h.getOwnedMedia(c)
+	if !ok {
+		return
+	}
+
+	utils.SuccessResponse(c, media)
+}
+
+// 獲取簽名下載地址
+func (h *MediaHandler) GetSignedURL(c *gin.Context) {
+	media, ok := h.getOwnedMedia(c)
+	if !ok {
+		return
+	}
+
+	signedURL, expiresAt, err := h.mediaService.SignedURL(c.Request.Context(), media)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "生成下載地址失敗")
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{
+		"url":        signedURL,
+		"expires_at": expiresAt,
+	})
+}
+
+// 刪除媒體文件
+func (h *MediaHandler) DeleteMedia(c *gin.Context) {
+	media, ok := h.getOwnedMedia(c)
+	if !ok {
+		return
+	}
+
+	postIDs, err := h.mediaService.DeleteMedia(c.Request.Context(), media)
+	if errors.Is(err, services.ErrMediaInUse) {
+		c.JSON(http.StatusConflict, utils.Response{
+			Code:    http.StatusConflict,
+			Message: err.Error(),
+			Data:    gin.H{"post_ids": postIDs},
+		})
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "刪除文件失敗")
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{"message": "文件刪除成功"})
+}
+
+// 輸出文件內容：圖片可直接嵌入文章，其他文件需要有效簽名
+func (h *MediaHandler) ServeFile(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	media, err := h.mediaService.GetMediaByKey(key)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "文件不存在")
+		return
+	}
+
+	isImage := strings.HasPrefix(media.MimeType, "image/")
+	if !isImage && !storage.VerifySignature(key, c.Query("expires"), c.Query("signature")) {
+		utils.ErrorResponse(c, http.StatusForbidden, "下載地址無效或已過期")
+		return
+	}
+
+	// 存儲路徑隨機生成且內容不會改變，圖片可長期緩存；其他文件的簽名地址會過期，不允許共享緩存
+	if isImage {
+		c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		c.Header("Cache-Control", "private, no-cache")
+	}
+	if utils.CheckNotModified(c, utils.ContentETag([]byte(media.StorageKey)), media.CreatedAt) {
+		return
+	}
+
+	reader, err := h.mediaService.Open(c.Request.Context(), media)
+	if err != nil {
+		c.Header("Cache-Control", "no-store")
+		utils.ErrorResponse(c, http.StatusNotFound, "文件不存在")
+		return
+	}
+	defer reader.Close()
+
+	disposition := "inline"
+	if !isImage {
+		disposition = "attachment"
+	}
+	c.DataFromReader(http.StatusOK, media.Size, media.MimeType, reader, map[string]string{
+		"Content-Disposition":    fmt.Sprintf("%s; filename*=UTF-8''%s", disposition, strings.ReplaceAll(url.QueryEscape(media.Filename), "+", "%20")),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
@@ -1,26 +1,57 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
+	"backend/config"
+	"backend/internal/models"
 	"backend/internal/services"
 	"backend/pkg/utils"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type PostHandler struct {
-	postService *services.PostService
+	postService         *services.PostService
+	reactionService     *services.ReactionService
+	bookmarkService     *services.BookmarkService
+	renderService       *services.RenderService
+	collaboratorService *services.CollaboratorService
+	seriesService       *services.SeriesService
 }
 
 func NewPostHandler() *PostHandler {
 	return &PostHandler{
-		postService: services.NewPostService(),
+		postService:         services.NewPostService(),
+		reactionService:     services.NewReactionService(),
+		bookmarkService:     services.NewBookmarkService(),
+		renderService:       services.NewRenderService(),
+		collaboratorService: services.NewCollaboratorService(),
+		seriesService:       services.NewSeriesService(),
 	}
 }
 
+// 獲取當前用戶對文章的權限，返回 false 時已寫入錯誤響應
+func (h *PostHandler) postPermission(c *gin.Context, post *models.Post) (services.PostPermission, bool) {
+	perm, err := h.collaboratorService.GetPermission(post, currentActor(c))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "檢查文章權限失敗")
+		return perm, false
+	}
+	return perm, true
+}
+
 // 創建文章請求結構
 type CreatePostRequest struct {
 	Title   string `json:"title" binding:"required,min=1,max=200"`
@@ -28,6 +59,8 @@ type CreatePostRequest struct {
 	Summary string `json:"summary"`
 	Status  string `json:"status"`
 	TagIDs  []uint `json:"tag_ids"`
+
+	CategoryID *uint `json:"category_id"`
 }
 
 // 創建文章
@@ -47,13 +80,21 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 
 	// 設置默認值
 	if req.Status == "" {
-		req.Status = "draft"
+		req.Status = models.PostStatusDraft
 	}
-	if req.Summary == "" && len(req.Content) > 200 {
-		req.Summary = req.Content[:200] + "..."
+	if err := services.CheckInitialPostStatus(req.Status, currentActor(c)); err != nil {
+		utils.ErrorResponse(c, postStatusErrorCode(err), err.Error())
+		return
+	}
+	if req.Summary == "" {
+		req.Summary = services.GenerateSummary(req.Content)
 	}
 
-	post, err := h.postService.CreatePost(req.Title, req.Content, req.Summary, req.Status, authorID.(uint), req.TagIDs)
+	post, err := h.postService.CreatePost(req.Title, req.Content, req.Summary, req.Status, authorID.(uint), req.CategoryID, req.TagIDs)
+	if errors.Is(err, services.ErrCategoryNotFound) {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "創建文章失敗: "+err.Error())
 		return
@@ -64,18 +105,36 @@ func (h *PostHandler) CreatePost(c *gin.Context) {
 
 // 獲取文章列表
 func (h *PostHandler) GetPosts(c *gin.Context) {
-	page, limit := utils.GetPaginationParams(c)
-	status := c.Query("status")
-	authorIDStr := c.Query("author_id")
+	filter, sort, err := parsePostListQuery(c)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	actor := currentActor(c)
+	filter.Reader = &actor
 
-	var authorID uint
-	if authorIDStr != "" {
-		if id, err := strconv.ParseUint(authorIDStr, 10, 32); err == nil {
-			authorID = uint(id)
+	h.listPosts(c, filter, sort)
+}
+
+// 輸出文章列表：帶 cursor 參數時使用游標分頁，否則使用頁碼分頁
+func (h *PostHandler) listPosts(c *gin.Context, filter services.PostFilter, sort []services.SortField) {
+	if cursor, useCursor, limit := utils.GetCursorParams(c); useCursor {
+		posts, next, prev, err := h.postService.GetPostsByCursor(cursor, limit, filter, sort)
+		if errors.Is(err, utils.ErrInvalidCursor) || errors.Is(err, services.ErrCursorSortMismatch) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
 		}
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "獲取文章列表失敗")
+			return
+		}
+
+		utils.CursorPaginatedSuccessResponse(c, posts, limit, next, prev)
+		return
 	}
 
-	posts, total, err := h.postService.GetPosts(page, limit, status, authorID)
+	page, limit := utils.GetPaginationParams(c)
+	posts, total, err := h.postService.GetPosts(page, limit, filter, sort)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取文章列表失敗")
 		return
@@ -84,6 +143,104 @@ func (h *PostHandler) GetPosts(c *gin.Context) {
 	utils.PaginatedSuccessResponse(c, posts, page, limit, total)
 }
 
+// 解析文章列表的篩選與排序參數
+func parsePostListQuery(c *gin.Context) (services.PostFilter, []services.SortField, error) {
+	query := c.Request.URL.Query()
+	filter, err := parsePostFilter(query)
+	if err != nil {
+		return filter, nil, err
+	}
+
+	sort, err := services.ParsePostSort(query.Get("sort"))
+	if err != nil {
+		return filter, nil, err
+	}
+	return filter, sort, nil
+}
+
+// 解析文章篩選條件，列表查詢參數與批量操作的篩選表達式共用
+func parsePostFilter(query url.Values) (services.PostFilter, error) {
+	var filter services.PostFilter
+	var err error
+
+	filter.Status = query.Get("status")
+	if filter.Status != "" && !services.IsValidPostStatus(filter.Status) {
+		return filter, services.ErrInvalidPostStatus
+	}
+	if filter.AuthorID, err = parseUintQuery(query, "author_id"); err != nil {
+		return filter, err
+	}
+	if filter.CategoryID, err = parseUintQuery(query, "category_id"); err != nil {
+		return filter, err
+	}
+	if tagIDs := query.Get("tag_ids"); tagIDs != "" {
+		for _, part := range strings.Split(tagIDs, ",") {
+			id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 32)
+			if err != nil || id == 0 {
+				return filter, fmt.Errorf("無效的標籤ID: %q", part)
+			}
+			filter.TagIDs = append(filter.TagIDs, uint(id))
+		}
+	}
+	switch query.Get("tag_match") {
+	case "", "any":
+	case "all":
+		filter.MatchAllTag = true
+	default:
+		return filter, errors.New("tag_match 只能是 any 或 all")
+	}
+	filter.TitlePrefix = strings.TrimSpace(query.Get("title_prefix"))
+
+	for _, r := range []struct {
+		key   string
+		dst   **time.Time
+		isEnd bool
+	}{
+		{"created_from", &filter.CreatedFrom, false},
+		{"created_to", &filter.CreatedTo, true},
+		{"updated_from", &filter.UpdatedFrom, false},
+		{"updated_to", &filter.UpdatedTo, true},
+	} {
+		if *r.dst, err = parseTimeQuery(query, r.key, r.isEnd); err != nil {
+			return filter, err
+		}
+	}
+	return filter, nil
+}
+
+// 解析正整數查詢參數，缺省時返回 0
+func parseUintQuery(query url.Values, key string) (uint, error) {
+	value := query.Get(key)
+	if value == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("無效的 %s: %q", key, value)
+	}
+	return uint(id), nil
+}
+
+// 解析時間查詢參數，支持 RFC3339 與 YYYY-MM-DD
+// 只有日期的結束時間包含當天，即取次日零點作為開區間上限
+func parseTimeQuery(query url.Values, key string, isEnd bool) (*time.Time, error) {
+	value := query.Get(key)
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("無效的 %s: %q（格式 YYYY-MM-DD 或 RFC3339）", key, value)
+	}
+	if isEnd {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
 // 根據ID獲取文章
 func (h *PostHandler) GetPost(c *gin.Context) {
 	idStr := c.Param("id")
@@ -99,10 +256,68 @@ func (h *PostHandler) GetPost(c *gin.Context) {
 		return
 	}
 
-	// 增加瀏覽量
-	go h.postService.IncrementViewCount(uint(id))
+	h.respondPost(c, post)
+}
 
-	utils.SuccessResponse(c, post)
+// 根據 slug 獲取文章，舊 slug 跳轉到當前地址
+func (h *PostHandler) GetPostBySlug(c *gin.Context) {
+	post, currentSlug, err := h.postService.GetPostBySlug(c.Param("slug"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "文章不存在")
+		return
+	}
+	if currentSlug != "" {
+		c.Redirect(http.StatusMovedPermanently, "/api/posts/slug/"+url.PathEscape(currentSlug))
+		return
+	}
+
+	h.respondPost(c, post)
+}
+
+// 返回文章詳情
+func (h *PostHandler) respondPost(c *gin.Context, post *models.Post) {
+	// 未發布或不公開的文章需要檢查權限
+	if !checkPostAccess(c, h.collaboratorService, post) {
+		return
+	}
+
+	// 記錄瀏覽量，由計數器去重後批量寫入
+	services.Views.Record(post, viewerID(c), currentActor(c).UserID, viewReferrer(c))
+
+	if err := h.attachEngagement(post, currentActor(c).UserID); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取文章互動數據失敗")
+		return
+	}
+
+	rendered, err := h.renderService.GetRendered(post)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "渲染文章失敗")
+		return
+	}
+	post.Rendered = rendered
+
+	if post.Series, err = h.seriesService.GetPostNav(post, currentActor(c)); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取文章系列失敗")
+		return
+	}
+
+	utils.ResourceResponse(c, post, post.Version, post.UpdatedAt)
+}
+
+// 附加表情回應與收藏統計
+func (h *PostHandler) attachEngagement(post *models.Post, userID uint) error {
+	var err error
+	if post.Reactions, err = h.reactionService.GetReactionCounts(post.ID); err != nil {
+		return err
+	}
+	if post.MyReactions, err = h.reactionService.GetUserReactions(post.ID, userID); err != nil {
+		return err
+	}
+	if post.BookmarkCount, err = h.bookmarkService.CountBookmarks(post.ID); err != nil {
+		return err
+	}
+	post.Bookmarked, err = h.bookmarkService.IsBookmarked(post.ID, userID)
+	return err
 }
 
 // 更新文章請求結構
@@ -112,6 +327,12 @@ type UpdatePostRequest struct {
 	Summary string `json:"summary"`
 	Status  string `json:"status"`
 	TagIDs  []uint `json:"tag_ids"`
+
+	// 傳 0 表示取消分類
+	CategoryID *uint `json:"category_id"`
+
+	// 客戶端持有的版本號，未提供 If-Match 請求頭時必填
+	Version *uint `json:"version"`
 }
 
 // 更新文章
@@ -129,6 +350,11 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 		return
 	}
 
+	version, ok := requestVersion(c, req.Version, true)
+	if !ok {
+		return
+	}
+
 	// 檢查文章是否存在
 	existingPost, err := h.postService.GetPostByID(uint(id))
 	if err != nil {
@@ -136,13 +362,20 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 		return
 	}
 
-	// 檢查權限：只有作者和管理員可以編輯
-	currentUserID, _ := c.Get("user_id")
-	currentRole, _ := c.Get("role")
-	if currentRole != "admin" && existingPost.AuthorID != currentUserID.(uint) {
+	// 檢查權限：作者、共同作者、編輯協作者和管理員可以編輯
+	perm, ok := h.postPermission(c, existingPost)
+	if !ok {
+		return
+	}
+	if !perm.Edit {
 		utils.ErrorResponse(c, http.StatusForbidden, "沒有權限編輯此文章")
 		return
 	}
+	// 修改狀態需要變更狀態的權限
+	if req.Status != "" && req.Status != existingPost.Status && !perm.ChangeStatus {
+		utils.ErrorResponse(c, http.StatusForbidden, "沒有權限變更此文章狀態")
+		return
+	}
 
 	updates := make(map[string]interface{})
 
@@ -154,18 +387,38 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 	}
 	if req.Summary != "" {
 		updates["summary"] = req.Summary
+	} else if req.Content != "" && existingPost.Summary == services.GenerateSummary(existingPost.Content) {
+		// 自動生成的摘要隨內容更新
+		updates["summary"] = services.GenerateSummary(req.Content)
 	}
 	if req.Status != "" {
 		updates["status"] = req.Status
 	}
+	if req.CategoryID != nil {
+		if *req.CategoryID == 0 {
+			updates["category_id"] = nil
+		} else {
+			updates["category_id"] = *req.CategoryID
+		}
+	}
 
-	post, err := h.postService.UpdatePost(uint(id), updates, req.TagIDs)
+	post, err := h.postService.UpdatePost(uint(id), version, updates, req.TagIDs, currentActor(c))
 	if err != nil {
+		if errors.Is(err, services.ErrVersionConflict) {
+			if current, err := h.postService.GetPostByID(uint(id)); err == nil {
+				respondVersionConflict(c, current, current.Version)
+				return
+			}
+		}
+		if code := postStatusErrorCode(err); code != http.StatusInternalServerError {
+			utils.ErrorResponse(c, code, err.Error())
+			return
+		}
 		utils.ErrorResponse(c, http.StatusInternalServerError, "更新文章失敗: "+err.Error())
 		return
 	}
 
-	utils.SuccessResponse(c, post)
+	utils.ResourceResponse(c, post, post.Version, post.UpdatedAt)
 }
 
 // 刪除文章
@@ -185,9 +438,11 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 	}
 
 	// 檢查權限：只有作者和管理員可以刪除
-	currentUserID, _ := c.Get("user_id")
-	currentRole, _ := c.Get("role")
-	if currentRole != "admin" && existingPost.AuthorID != currentUserID.(uint) {
+	perm, ok := h.postPermission(c, existingPost)
+	if !ok {
+		return
+	}
+	if !perm.Delete {
 		utils.ErrorResponse(c, http.StatusForbidden, "沒有權限刪除此文章")
 		return
 	}
@@ -201,11 +456,8 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 	utils.SuccessResponse(c, gin.H{"message": "文章刪除成功"})
 }
 
-// 獲取我的文章
+// 獲取我的文章，包括作為共同作者的文章
 func (h *PostHandler) GetMyPosts(c *gin.Context) {
-	page, limit := utils.GetPaginationParams(c)
-	status := c.Query("status")
-
 	// 獲取當前用戶ID
 	authorID, exists := c.Get("user_id")
 	if !exists {
@@ -213,13 +465,14 @@ func (h *PostHandler) GetMyPosts(c *gin.Context) {
 		return
 	}
 
-	posts, total, err := h.postService.GetPosts(page, limit, status, authorID.(uint))
+	filter, sort, err := parsePostListQuery(c)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取文章列表失敗")
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
+	filter.MemberID = authorID.(uint)
 
-	utils.PaginatedSuccessResponse(c, posts, page, limit, total)
+	h.listPosts(c, filter, sort)
 }
 
 // 搜索文章
@@ -234,7 +487,10 @@ func (h *PostHandler) SearchPosts(c *gin.Context) {
 
 	// 這裡可以實現更複雜的搜索邏輯
 	// 暫時使用簡單的標題和內容搜索
-	posts, _, err := h.postService.GetPosts(page, limit, "published", 0)
+	sort, _ := services.ParsePostSort(services.DefaultPostSort)
+	actor := currentActor(c)
+	filter := services.PostFilter{Status: models.PostStatusPublished, Reader: &actor}
+	posts, _, err := h.postService.GetPosts(page, limit, filter, sort)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "搜索失敗")
 		return
@@ -251,3 +507,322 @@ func (h *PostHandler) SearchPosts(c *gin.Context) {
 
 	utils.PaginatedSuccessResponse(c, filteredPosts, page, limit, int64(len(filteredPosts)))
 }
+
+// 變更文章狀態請求結構
+type ChangePostStatusRequest struct {
+	Status  string `json:"status" binding:"required"`
+	Comment string `json:"comment"`
+}
+
+// 變更文章狀態
+func (h *PostHandler) ChangePostStatus(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "無效的文章ID")
+		return
+	}
+
+	var req ChangePostStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "請求參數錯誤: "+err.Error())
+		return
+	}
+
+	existingPost, err := h.postService.GetPostByID(uint(id))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "文章不存在")
+		return
+	}
+
+	// 檢查權限：作者、共同作者和審核者可以變更狀態
+	perm, ok := h.postPermission(c, existingPost)
+	if !ok {
+		return
+	}
+	if !perm.ChangeStatus {
+		utils.ErrorResponse(c, http.StatusForbidden, "沒有權限變更此文章狀態")
+		return
+	}
+
+	post, err := h.postService.ChangePostStatus(uint(id), req.Status, currentActor(c), req.Comment)
+	if err != nil {
+		utils.ErrorResponse(c, postStatusErrorCode(err), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, post)
+}
+
+// 設置可見範圍請求結構
+type SetVisibilityRequest struct {
+	Visibility   string   `json:"visibility" binding:"required"`
+	VisibleRoles []string `json:"visible_roles"` // restricted 時可查看的角色
+	Password     string   `json:"password" binding:"max=72"`
+}
+
+// 設置文章可見範圍
+func (h *PostHandler) SetVisibility(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "無效的文章ID")
+		return
+	}
+
+	var req SetVisibilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "請求參數錯誤: "+err.Error())
+		return
+	}
+
+	existingPost, err := h.postService.GetPostByID(uint(id))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "文章不存在")
+		return
+	}
+
+	// 檢查權限：只有作者和管理員可以設置可見範圍
+	perm, ok := h.postPermission(c, existingPost)
+	if !ok {
+		return
+	}
+	if !perm.Manage {
+		utils.ErrorResponse(c, http.StatusForbidden, "沒有權限設置此文章的可見範圍")
+		return
+	}
+
+	post, err := h.postService.SetVisibility(existingPost.ID, req.Visibility, req.VisibleRoles, req.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidVisibility), errors.Is(err, services.ErrInvalidVisibleRole),
+			errors.Is(err, services.ErrPostPasswordRequired):
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "設置可見範圍失敗")
+		}
+		return
+	}
+
+	utils.ResourceResponse(c, post, post.Version, post.UpdatedAt)
+}
+
+// 解鎖文章請求結構
+type UnlockPostRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// 校驗密碼保護文章的密碼，返回短期有效的訪問令牌，之後通過 X-Post-Grant 請求頭或 grant 參數訪問文章
+func (h *PostHandler) UnlockPost(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "無效的文章ID")
+		return
+	}
+
+	var req UnlockPostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "請求參數錯誤: "+err.Error())
+		return
+	}
+
+	post, err := h.postService.GetPostByID(uint(id))
+	if err != nil || post.Status != models.PostStatusPublished {
+		utils.ErrorResponse(c, http.StatusNotFound, "文章不存在")
+		return
+	}
+
+	// 在校驗密碼之前計數，鎖定期間不再校驗
+	keys := services.UnlockKeys(post.ID, currentActor(c).UserID, c.ClientIP())
+	if wait, ok := services.UnlockAttempts.Attempt(keys...); !ok {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		utils.ErrorResponse(c, http.StatusTooManyRequests, services.ErrTooManyUnlockAttempts.Error())
+		return
+	}
+
+	grant, expiresAt, err := h.postService.UnlockPost(post, req.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrPostNotProtected):
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, services.ErrWrongPostPassword):
+			utils.ErrorResponse(c, http.StatusForbidden, err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "解鎖文章失敗")
+		}
+		return
+	}
+
+	services.UnlockAttempts.Reset(keys...)
+
+	utils.SuccessResponse(c, gin.H{
+		"grant":      grant,
+		"expires_at": expiresAt,
+	})
+}
+
+// 審核文章請求結構
+type ReviewPostRequest struct {
+	Comment string `json:"comment"`
+}
+
+// 審核通過
+func (h *PostHandler) ApprovePost(c *gin.Context) {
+	h.reviewPost(c, true)
+}
+
+// 審核退回
+func (h *PostHandler) RejectPost(c *gin.Context) {
+	h.reviewPost(c, false)
+}
+
+func (h *PostHandler) reviewPost(c *gin.Context, approve bool) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "無效的文章ID")
+		return
+	}
+
+	var req ReviewPostRequest
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "請求參數錯誤: "+err.Error())
+		return
+	}
+	req.Comment = strings.TrimSpace(req.Comment)
+	if !approve && req.Comment == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "退回文章需要填寫審核意見")
+		return
+	}
+
+	post, err := h.postService.ReviewPost(uint(id), approve, currentActor(c), req.Comment)
+	if err != nil {
+		utils.ErrorResponse(c, postStatusErrorCode(err), err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, post)
+}
+
+// 獲取文章狀態歷史
+func (h *PostHandler) GetPostStatusHistory(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "無效的文章ID")
+		return
+	}
+
+	existingPost, err := h.postService.GetPostByID(uint(id))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "文章不存在")
+		return
+	}
+
+	perm, ok := h.postPermission(c, existingPost)
+	if !ok {
+		return
+	}
+	if !perm.Read {
+		utils.ErrorResponse(c, http.StatusForbidden, "沒有權限查看此文章狀態記錄")
+		return
+	}
+
+	history, err := h.postService.GetPostStatusHistory(uint(id))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取狀態記錄失敗")
+		return
+	}
+
+	utils.SuccessResponse(c, history)
+}
+
+// 狀態相關錯誤對應的 HTTP 狀態碼
+func postStatusErrorCode(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidPostStatus), errors.Is(err, services.ErrCategoryNotFound):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrStatusTransitionNotAllowed), errors.Is(err, services.ErrPostNotInReview):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrReviewerRequired):
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
+
+// 導出文章為 Markdown 壓縮包，支持與文章列表相同的篩選參數
+func (h *PostHandler) ExportPosts(c *gin.Context) {
+	filter, err := parsePostFilter(c.Request.URL.Query())
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// 先寫入臨時文件，出錯時仍可返回 JSON 錯誤
+	tmp, err := os.CreateTemp("", "posts-export-*.zip")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "導出文章失敗")
+		return
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if _, err := h.postService.ExportPosts(tmp, filter); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "導出文章失敗")
+		return
+	}
+
+	filename := fmt.Sprintf("posts-%s.zip", time.Now().Format("20060102-150405"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.File(tmp.Name())
+}
+
+// 導入文章，上傳 zip 壓縮包或單個 Markdown 文件
+func (h *PostHandler) ImportPosts(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, config.AppConfig.MaxUploadSize+1<<20)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "請選擇要導入的文件")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "讀取上傳文件失敗")
+		return
+	}
+	defer file.Close()
+
+	actor := currentActor(c)
+	var report *services.ImportReport
+	switch strings.ToLower(filepath.Ext(fileHeader.Filename)) {
+	case ".zip":
+		report, err = h.postService.ImportArchive(file, fileHeader.Size, actor)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidArchive) {
+				utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			utils.ErrorResponse(c, http.StatusInternalServerError, "導入文章失敗")
+			return
+		}
+	case ".md", ".markdown":
+		data, err := io.ReadAll(io.LimitReader(file, services.MaxImportFileSize+1))
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "讀取上傳文件失敗")
+			return
+		}
+		if len(data) > services.MaxImportFileSize {
+			utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, fmt.Sprintf("文件超過 %d MB", services.MaxImportFileSize>>20))
+			return
+		}
+		report = h.postService.ImportMarkdown(fileHeader.Filename, data, actor)
+	default:
+		utils.ErrorResponse(c, http.StatusUnsupportedMediaType, "只支持 .zip、.md 或 .markdown 文件")
+		return
+	}
+
+	utils.SuccessResponse(c, report)
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend/internal/models"
+	"backend/internal/services"
+	"backend/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PreviewHandler struct {
+	postService         *services.PostService
+	collaboratorService *services.CollaboratorService
+	previewService      *services.PreviewService
+	renderService       *services.RenderService
+}
+
+func NewPreviewHandler() *PreviewHandler {
+	return &PreviewHandler{
+		postService:         services.NewPostService(),
+		collaboratorService: services.NewCollaboratorService(),
+		previewService:      services.NewPreviewService(),
+		renderService:       services.NewRenderService(),
+	}
+}
+
+// 創建預覽鏈接請求結構
+type CreatePreviewRequest struct {
+	ExpiresIn string `json:"expires_in"` // 有效期，如 48h，不填使用默認值
+}
+
+// 獲取文章並檢查是否可以管理預覽鏈接（作者與管理員），返回 false 時已寫入錯誤響應
+func (h *PreviewHandler) getPost(c *gin.Context) (*models.Post, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "無效的文章ID")
+		return nil, false
+	}
+
+	post, err := h.postService.GetPostByID(uint(id))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "文章不存在")
+		return nil, false
+	}
+
+	perm, err := h.collaboratorService.GetPermission(post, currentActor(c))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "檢查文章權限失敗")
+		return nil, false
+	}
+	if !perm.Manage {
+		utils.ErrorResponse(c, http.StatusForbidden, "沒有權限管理此文章的預覽鏈接")
+		return nil, false
+	}
+	return post, true
+}
+
+// 創建預覽鏈接
+func (h *PreviewHandler) CreatePreview(c *gin.Context) {
+	post, ok := h.getPost(c)
+	if !ok {
+		return
+	}
+
+	var req CreatePreviewRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "請求參數錯誤: "+err.Error())
+			return
+		}
+	}
+
+	var expires time.Duration
+	if req.ExpiresIn != "" {
+		var err error
+		if expires, err = time.ParseDuration(req.ExpiresIn); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "無效的 expires_in")
+			return
+		}
+	}
+
+	preview, err := h.previewService.CreatePreview(post.ID, currentActor(c).UserID, expires)
+	if err != nil {
+		if errors.Is(err, services.ErrPreviewExpiresRange) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "創建預覽鏈接失敗")
+		return
+	}
+
+	utils.SuccessResponse(c, preview)
+}
+
+// 獲取文章的預覽鏈接
+func (h *PreviewHandler) GetPreviews(c *gin.Context) {
+	post, ok := h.getPost(c)
+	if !ok {
+		return
+	}
+
+	previews, err := h.previewService.GetPreviews(post.ID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取預覽鏈接失敗")
+		return
+	}
+
+	utils.SuccessResponse(c, previews)
+}
+
+// 撤銷預覽鏈接
+func (h *PreviewHandler) RevokePreview(c *gin.Context) {
+	post, ok := h.getPost(c)
+	if !ok {
+		return
+	}
+
+	previewID, err := strconv.ParseUint(c.Param("preview_id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "無效的預覽鏈接ID")
+		return
+	}
+
+	if err := h.previewService.RevokePreview(post.ID, uint(previewID)); err != nil {
+		if errors.Is(err, services.ErrPreviewNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "撤銷預覽鏈接失敗")
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{"message": "預覽鏈接已撤銷"})
+}
+
+// 通過預覽令牌只讀查看文章，無需登錄，不計入瀏覽量
+func (h *PreviewHandler) ViewPreview(c *gin.Context) {
+	// 預覽內容不允許被緩存或被搜索引擎收錄
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("X-Robots-Tag", "noindex, nofollow")
+
+	post, err := h.previewService.ResolvePreview(c.Param("token"))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPreviewToken) {
+			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取文章失敗")
+		return
+	}
+
+	rendered, err := h.renderService.GetRendered(post)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "渲染文章失敗")
+		return
+	}
+	post.Rendered = rendered
+
+	utils.SuccessResponse(c, post)
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"backend/internal/services"
+	"backend/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReactionHandler struct {
+	reactionService     *services.ReactionService
+	postService         *services.PostService
+	collaboratorService *services.CollaboratorService
+}
+
+func NewReactionHandler() *ReactionHandler {
+	return &ReactionHandler{
+		reactionService:     services.NewReactionService(),
+		postService:         services.NewPostService(),
+		collaboratorService: services.NewCollaboratorService(),
+	}
+}
+
+// 獲取支持的表情列表
+func (h *ReactionHandler) GetReactionTypes(c *gin.Context) {
+	utils.SuccessResponse(c, services.ReactionTypes)
+}
+
+// 添加表情回應
+func (h *ReactionHandler) AddReaction(c *gin.Context) {
+	h.updateReaction(c, true)
+}
+
+// 移除表情回應
+func (h *ReactionHandler) RemoveReaction(c *gin.Context) {
+	h.updateReaction(c, false)
+}
+
+// 只能對有權查看的文章添加或移除表情回應
+func (h *ReactionHandler) updateReaction(c *gin.Context, add bool) {
+	post, ok := readablePost(c, h.postService, h.collaboratorService)
+	if !ok {
+		return
+	}
+	postID := post.ID
+
+	var err error
+	actor := currentActor(c)
+	reactionType := c.Param("type")
+	if add {
+		err = h.reactionService.AddReaction(postID, actor.UserID, reactionType)
+	} else {
+		err = h.reactionService.RemoveReaction(postID, actor.UserID, reactionType)
+	}
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidReactionType) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusNotFound, "文章不存在")
+		return
+	}
+
+	counts, err := h.reactionService.GetReactionCounts(postID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取表情統計失敗")
+		return
+	}
+	mine, err := h.reactionService.GetUserReactions(postID, actor.UserID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取表情統計失敗")
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{
+		"reactions":    counts,
+		"my_reactions": mine,
+	})
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"backend/config"
+	"backend/internal/services"
+	"backend/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RelatedHandler struct {
+	postService         *services.PostService
+	collaboratorService *services.CollaboratorService
+	relatedService      *services.RelatedService
+}
+
+func NewRelatedHandler() *RelatedHandler {
+	return &RelatedHandler{
+		postService:         services.NewPostService(),
+		collaboratorService: services.NewCollaboratorService(),
+		relatedService:      services.NewRelatedService(),
+	}
+}
+
+// 獲取相關文章，limit 默認取 RELATED_POSTS_COUNT，exclude_author 默認取 RELATED_EXCLUDE_AUTHOR
+func (h *RelatedHandler) GetRelatedPosts(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "無效的文章ID")
+		return
+	}
+
+	limit := config.AppConfig.RelatedPostsCount
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if limit, err = strconv.Atoi(limitStr); err != nil || limit < 1 {
+			utils.ErrorResponse(c, http.StatusBadRequest, "無效的 limit")
+			return
+		}
+	}
+	if limit < 1 || limit > services.MaxRelatedPosts {
+		limit = services.MaxRelatedPosts
+	}
+
+	excludeAuthor := config.AppConfig.RelatedExcludeAuthor
+	if v := c.Query("exclude_author"); v != "" {
+		if excludeAuthor, err = strconv.ParseBool(v); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "無效的 exclude_author")
+			return
+		}
+	}
+
+	post, err := h.postService.GetPostByID(uint(id))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "文章不存在")
+		return
+	}
+	if !checkPostAccess(c, h.collaboratorService, post) {
+		return
+	}
+
+	related, err := h.relatedService.GetRelated(post, currentActor(c), limit, excludeAuthor)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取相關文章失敗")
+		return
+	}
+
+	utils.SuccessResponse(c, related)
+}
@@ -0,0 +1,309 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/config"
+	"backend/internal/models"
+	"backend/internal/services"
+	"backend/internal/templates"
+	"backend/pkg/sitemap"
+	"backend/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const xmlContentType = "application/xml; charset=utf-8"
+
+// 正文中的第一張圖片，用作分享卡片配圖
+var firstImagePattern = regexp.MustCompile(`<img[^>]+src="([^"]+)"`)
+
+type SEOHandler struct {
+	sitemapService *services.SitemapService
+	postService    *services.PostService
+	renderService  *services.RenderService
+	settingService *services.SettingService
+	feedService    *services.FeedService
+}
+
+func NewSEOHandler() *SEOHandler {
+	return &SEOHandler{
+		sitemapService: services.NewSitemapService(),
+		postService:    services.NewPostService(),
+		renderService:  services.NewRenderService(),
+		settingService: services.NewSettingService(),
+		feedService:    services.NewFeedService(),
+	}
+}
+
+// Sitemap 輸出站點地圖，地址數超過單文件上限時輸出索引
+func (h *SEOHandler) Sitemap(c *gin.Context) {
+	parts, total, err := h.sitemapService.Parts()
+	if err != nil {
+		c.String(http.StatusInternalServerError, "生成站點地圖失敗")
+		return
+	}
+
+	if total <= int64(h.sitemapService.PageSize()) {
+		var urls []sitemap.URL
+		for _, part := range parts {
+			partURLs, err := h.sitemapService.URLs(part.Section, part.Page)
+			if err != nil {
+				c.String(http.StatusInternalServerError, "生成站點地圖失敗")
+				return
+			}
+			urls = append(urls, partURLs...)
+		}
+		h.respondXML(c, sitemap.URLSet, urls)
+		return
+	}
+
+	urls := make([]sitemap.URL, 0, len(parts))
+	for _, part := range parts {
+		urls = append(urls, sitemap.URL{
+			Loc:     fmt.Sprintf("%s/sitemaps/%s-%d.xml", config.AppConfig.SiteURL, part.Section, part.Page),
+			LastMod: part.LastMod,
+		})
+	}
+	h.respondXML(c, sitemap.Index, urls)
+}
+
+// SitemapFile 輸出站點地圖分片，如 /sitemaps/posts-1.xml
+func (h *SEOHandler) SitemapFile(c *gin.Context) {
+	name := strings.TrimSuffix(c.Param("name"), ".xml")
+	i := strings.LastIndex(name, "-")
+	if i < 0 {
+		c.String(http.StatusNotFound, "站點地圖不存在")
+		return
+	}
+	page, err := strconv.Atoi(name[i+1:])
+	if err != nil {
+		c.String(http.StatusNotFound, "站點地圖不存在")
+		return
+	}
+
+	urls, err := h.sitemapService.URLs(name[:i], page)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "生成站點地圖失敗")
+		return
+	}
+	if len(urls) == 0 {
+		c.String(http.StatusNotFound, "站點地圖不存在")
+		return
+	}
+	h.respondXML(c, sitemap.URLSet, urls)
+}
+
+func (h *SEOHandler) respondXML(c *gin.Context, encode func([]sitemap.URL) ([]byte, error), urls []sitemap.URL) {
+	body, err := encode(urls)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "生成站點地圖失敗")
+		return
+	}
+	if utils.CheckNotModified(c, utils.ContentETag(body), time.Time{}) {
+		return
+	}
+	c.Data(http.StatusOK, xmlContentType, body)
+}
+
+// 文章頁面模板數據
+type postPage struct {
+	Post            *models.Post
+	SiteName        string
+	SiteDescription string
+	SiteURL         string
+	URL             string
+	Description     string
+	Image           string
+	Published       time.Time
+	ReadingTime     int
+	Content         template.HTML
+}
+
+// PostPage 服務端渲染的文章頁面，供搜索引擎與社交平台抓取
+func (h *SEOHandler) PostPage(c *gin.Context) {
+	post, currentSlug, err := h.postService.GetPostBySlug(c.Param("slug"))
+	// 頁面可被 CDN 緩存，只輸出已發布的公開文章
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && currentSlug == "" &&
+		(post.Status != models.PostStatusPublished || post.Visibility != models.PostVisibilityPublic)) {
+		c.String(http.StatusNotFound, "文章不存在")
+		return
+	}
+	if err != nil {
+		c.String(http.StatusInternalServerError, "獲取文章失敗")
+		return
+	}
+	if currentSlug != "" {
+		c.Redirect(http.StatusMovedPermanently, "/posts/"+url.PathEscape(currentSlug))
+		return
+	}
+
+	rendered, err := h.renderService.GetRendered(post)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "渲染文章失敗")
+		return
+	}
+
+	services.Views.Record(post, viewerID(c), 0, viewReferrer(c))
+
+	siteURL := config.AppConfig.SiteURL
+	page := postPage{
+		Post:            post,
+		SiteName:        h.settingService.Get("site_name", "Gin Admin"),
+		SiteDescription: h.settingService.Get("site_description", ""),
+		SiteURL:         siteURL,
+		URL:             services.PostURL(post),
+		Description:     post.Summary,
+		Published:       services.PostPublishedTime(post),
+		ReadingTime:     rendered.ReadingTime,
+		// 渲染結果已經過 HTML 清理
+		Content: template.HTML(rendered.HTML),
+	}
+	if page.Description == "" {
+		page.Description = page.SiteDescription
+	}
+	if m := firstImagePattern.FindStringSubmatch(rendered.HTML); m != nil {
+		page.Image = absoluteURL(siteURL, m[1])
+	}
+
+	var buf bytes.Buffer
+	if err := templates.Pages.ExecuteTemplate(&buf, "post.html", page); err != nil {
+		c.String(http.StatusInternalServerError, "渲染頁面失敗")
+		return
+	}
+
+	if utils.CheckNotModified(c, utils.ContentETag(buf.Bytes()), post.UpdatedAt) {
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// 列表頁模板數據
+type listPage struct {
+	Title           string
+	Description     string
+	SiteName        string
+	SiteDescription string
+	SiteURL         string
+	URL             string
+	FeedURL         string
+	Posts           []listPageItem
+	PrevURL         string
+	NextURL         string
+}
+
+type listPageItem struct {
+	Post      *models.Post
+	URL       string
+	Published time.Time
+}
+
+// CategoryPage 服務端渲染的分類文章列表
+func (h *SEOHandler) CategoryPage(c *gin.Context) {
+	category, err := h.sitemapService.GetCategoryByName(c.Param("name"))
+	if err != nil {
+		c.String(http.StatusNotFound, "分類不存在")
+		return
+	}
+	description := category.Description
+	if description == "" {
+		description = "分類「" + category.Name + "」下的文章"
+	}
+	h.respondList(c, services.FeedFilter{CategoryID: category.ID}, category.Name, description, services.CategoryURL(category), "")
+}
+
+// TagPage 服務端渲染的標籤文章列表
+func (h *SEOHandler) TagPage(c *gin.Context) {
+	tag, err := h.feedService.GetTagByName(c.Param("tag"))
+	if err != nil {
+		c.String(http.StatusNotFound, "標籤不存在")
+		return
+	}
+	h.respondList(c, services.FeedFilter{TagID: tag.ID}, tag.Name, "標籤「"+tag.Name+"」下的文章",
+		services.TagURL(tag), services.TagURL(tag)+"/feed.rss")
+}
+
+// 輸出文章列表頁，按 posts_per_page 分頁；沒有公開文章或頁碼超出範圍時返回 404
+func (h *SEOHandler) respondList(c *gin.Context, filter services.FeedFilter, title, description, pageURL, feedURL string) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.String(http.StatusNotFound, "頁面不存在")
+		return
+	}
+	limit := h.settingService.GetInt("posts_per_page", 10)
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+
+	posts, total, err := h.sitemapService.ListingPosts(filter, page, limit)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "獲取文章列表失敗")
+		return
+	}
+	if len(posts) == 0 {
+		c.String(http.StatusNotFound, "頁面不存在")
+		return
+	}
+
+	siteURL := config.AppConfig.SiteURL
+	data := listPage{
+		Title:           title,
+		Description:     description,
+		SiteName:        h.settingService.Get("site_name", "Gin Admin"),
+		SiteDescription: h.settingService.Get("site_description", ""),
+		SiteURL:         siteURL,
+		URL:             pageURL,
+		FeedURL:         feedURL,
+	}
+	if page > 1 {
+		data.URL = pageURL + "?page=" + strconv.Itoa(page)
+		data.PrevURL = pageURL
+		if page > 2 {
+			data.PrevURL += "?page=" + strconv.Itoa(page-1)
+		}
+	}
+	if int64(page*limit) < total {
+		data.NextURL = pageURL + "?page=" + strconv.Itoa(page+1)
+	}
+
+	var lastModified time.Time
+	for i := range posts {
+		data.Posts = append(data.Posts, listPageItem{
+			Post:      &posts[i],
+			URL:       services.PostURL(&posts[i]),
+			Published: services.PostPublishedTime(&posts[i]),
+		})
+		if posts[i].UpdatedAt.After(lastModified) {
+			lastModified = posts[i].UpdatedAt
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := templates.Pages.ExecuteTemplate(&buf, "list.html", data); err != nil {
+		c.String(http.StatusInternalServerError, "渲染頁面失敗")
+		return
+	}
+
+	if utils.CheckNotModified(c, utils.ContentETag(buf.Bytes()), lastModified) {
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// 將站內相對地址轉為絕對地址
+func absoluteURL(siteURL, ref string) string {
+	if strings.HasPrefix(ref, "/") && !strings.HasPrefix(ref, "//") {
+		return siteURL + ref
+	}
+	return ref
+}
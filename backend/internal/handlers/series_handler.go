@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"backend/internal/models"
+	"backend/internal/services"
+	"backend/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SeriesHandler struct {
+	seriesService       *services.SeriesService
+	postService         *services.PostService
+	collaboratorService *services.CollaboratorService
+}
+
+func NewSeriesHandler() *SeriesHandler {
+	return &SeriesHandler{
+		seriesService:       services.NewSeriesService(),
+		postService:         services.NewPostService(),
+		collaboratorService: services.NewCollaboratorService(),
+	}
+}
+
+// 系列請求結構
+type SeriesRequest struct {
+	Title       string `json:"title" binding:"required,min=1,max=200"`
+	Description string `json:"description"`
+}
+
+// 更新系列請求結構
+type UpdateSeriesRequest struct {
+	Title       string  `json:"title" binding:"max=200"`
+	Description *string `json:"description"`
+}
+
+// 加入系列請求結構
+type AddSeriesPostRequest struct {
+	PostID   uint `json:"post_id" binding:"required"`
+	Position int  `json:"position"` // 從 1 開始，不填時加到末尾
+}
+
+// 系列排序請求結構
+type ReorderSeriesRequest struct {
+	PostIDs []uint `json:"post_ids" binding:"required"`
+}
+
+// 是否可以管理系列：系列作者和管理員
+func canManageSeries(series *models.Series, actor services.Actor) bool {
+	return actor.IsAdmin() || series.AuthorID == actor.UserID
+}
+
+// 獲取路徑中的系列，manage 為 true 時檢查管理權限，返回 false 時已寫入錯誤響應
+func (h *SeriesHandler) getSeries(c *gin.Context, manage bool) (*models.Series, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "無效的系列ID")
+		return nil, false
+	}
+
+	series, err := h.seriesService.GetSeriesByID(uint(id))
+	if err != nil {
+		if errors.Is(err, services.ErrSeriesNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+			return nil, false
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取系列失敗")
+		return nil, false
+	}
+
+	if manage && !canManageSeries(series, currentActor(c)) {
+		utils.ErrorResponse(c, http.StatusForbidden, "沒有權限管理此系列")
+		return nil, false
+	}
+	return series, true
+}
+
+// 返回系列詳情與文章列表，讀者只能看到可見範圍內的已發布文章，系列作者和管理員可看到全部
+func (h *SeriesHandler) respondSeries(c *gin.Context, series *models.Series) {
+	var reader *services.Actor
+	if actor := currentActor(c); !canManageSeries(series, actor) {
+		reader = &actor
+	}
+	posts, err := h.seriesService.GetSeriesPosts(series.ID, reader)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取系列文章失敗")
+		return
+	}
+	series.Posts = posts
+	series.PostCount = int64(len(posts))
+
+	utils.SuccessResponse(c, series)
+}
+
+// 獲取系列列表，可按 author_id 篩選
+func (h *SeriesHandler) GetSeriesList(c *gin.Context) {
+	page, limit := utils.GetPaginationParams(c)
+
+	var authorID uint
+	if idStr := c.Query("author_id"); idStr != "" {
+		id, err := strconv.ParseUint(idStr, 10, 32)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "無效的 author_id")
+			return
+		}
+		authorID = uint(id)
+	}
+
+	list, total, err := h.seriesService.GetSeriesList(page, limit, authorID, currentActor(c))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取系列列表失敗")
+		return
+	}
+
+	utils.PaginatedSuccessResponse(c, list, page, limit, total)
+}
+
+// 創建系列
+func (h *SeriesHandler) CreateSeries(c *gin.Context) {
+	var req SeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "請求參數錯誤: "+err.Error())
+		return
+	}
+
+	series, err := h.seriesService.CreateSeries(req.Title, req.Description, currentActor(c).UserID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "創建系列失敗")
+		return
+	}
+
+	utils.SuccessResponse(c, series)
+}
+
+// 系列詳情頁：系列信息與按順序排列的文章
+func (h *SeriesHandler) GetSeries(c *gin.Context) {
+	series, ok := h.getSeries(c, false)
+	if !ok {
+		return
+	}
+
+	h.respondSeries(c, series)
+}
+
+// 更新系列
+func (h *SeriesHandler) UpdateSeries(c *gin.Context) {
+	series, ok := h.getSeries(c, true)
+	if !ok {
+		return
+	}
+
+	var req UpdateSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "請求參數錯誤: "+err.Error())
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if req.Title != "" {
+		updates["title"] = req.Title
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+
+	updated, err := h.seriesService.UpdateSeries(series.ID, updates)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "更新系列失敗")
+		return
+	}
+
+	h.respondSeries(c, updated)
+}
+
+// 刪除系列，其中的文章不會被刪除
+func (h *SeriesHandler) DeleteSeries(c *gin.Context) {
+	series, ok := h.getSeries(c, true)
+	if !ok {
+		return
+	}
+
+	if err := h.seriesService.DeleteSeries(series.ID); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "刪除系列失敗")
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{"message": "系列刪除成功"})
+}
+
+// 將文章加入系列或移動到指定位置，需要同時有系列的管理權限和文章的編輯權限
+func (h *SeriesHandler) AddPost(c *gin.Context) {
+	series, ok := h.getSeries(c, true)
+	if !ok {
+		return
+	}
+
+	var req AddSeriesPostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "請求參數錯誤: "+err.Error())
+		return
+	}
+
+	post, err := h.postService.GetPostByID(req.PostID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "文章不存在")
+		return
+	}
+	perm, err := h.collaboratorService.GetPermission(post, currentActor(c))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "檢查文章權限失敗")
+		return
+	}
+	if !perm.Edit {
+		utils.ErrorResponse(c, http.StatusForbidden, "沒有權限編輯此文章")
+		return
+	}
+
+	if err := h.seriesService.AddPost(series.ID, post.ID, req.Position); err != nil {
+		if errors.Is(err, services.ErrPostInOtherSeries) {
+			utils.ErrorResponse(c, http.StatusConflict, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "加入系列失敗")
+		return
+	}
+
+	h.respondSeries(c, series)
+}
+
+// 將文章移出系列
+func (h *SeriesHandler) RemovePost(c *gin.Context) {
+	series, ok := h.getSeries(c, true)
+	if !ok {
+		return
+	}
+
+	postID, err := strconv.ParseUint(c.Param("post_id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "無效的文章ID")
+		return
+	}
+
+	if err := h.seriesService.RemovePost(series.ID, uint(postID)); err != nil {
+		if errors.Is(err, services.ErrPostNotInSeries) {
+			utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "移出系列失敗")
+		return
+	}
+
+	h.respondSeries(c, series)
+}
+
+// 重新排列系列中的文章
+func (h *SeriesHandler) ReorderPosts(c *gin.Context) {
+	series, ok := h.getSeries(c, true)
+	if !ok {
+		return
+	}
+
+	var req ReorderSeriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "請求參數錯誤: "+err.Error())
+		return
+	}
+
+	if err := h.seriesService.ReorderPosts(series.ID, req.PostIDs); err != nil {
+		if errors.Is(err, services.ErrSeriesOrderMismatch) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "排序失敗")
+		return
+	}
+
+	h.respondSeries(c, series)
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"backend/internal/services"
+	"backend/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type TrashHandler struct {
+	trashService *services.TrashService
+}
+
+func NewTrashHandler() *TrashHandler {
+	return &TrashHandler{
+		trashService: services.NewTrashService(),
+	}
+}
+
+// 獲取回收站中的文章
+func (h *TrashHandler) GetDeletedPosts(c *gin.Context) {
+	page, limit := utils.GetPaginationParams(c)
+
+	posts, total, err := h.trashService.GetDeletedPosts(page, limit)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取回收站文章失敗")
+		return
+	}
+
+	utils.PaginatedSuccessResponse(c, posts, page, limit, total)
+}
+
+// 恢復文章
+func (h *TrashHandler) RestorePost(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "無效的文章ID")
+		return
+	}
+
+	post, err := h.trashService.RestorePost(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, "回收站中沒有此文章")
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "恢復文章失敗")
+		return
+	}
+
+	utils.SuccessResponse(c, post)
+}
+
+// 永久刪除文章
+func (h *TrashHandler) PurgePost(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "無效的文章ID")
+		return
+	}
+
+	if err := h.trashService.PurgePost(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.ErrorResponse(c, http.StatusNotFound, "回收站中沒有此文章")
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "永久刪除文章失敗")
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{"message": "文章已永久刪除"})
+}
+
+// 獲取回收站中的用戶
+func (h *TrashHandler) GetDeletedUsers(c *gin.Context) {
+	page, limit := utils.GetPaginationParams(c)
+
+	users, total, err := h.trashService.GetDeletedUsers(page, limit)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取回收站用戶失敗")
+		return
+	}
+
+	utils.PaginatedSuccessResponse(c, users, page, limit, total)
+}
+
+// 恢復用戶，用戶名或郵箱已被其他帳號使用時可同時指定新值
+type RestoreUserRequest struct {
+	Username string `json:"username" binding:"omitempty,min=3,max=50"`
+	Email    string `json:"email" binding:"omitempty,email"`
+}
+
+func (h *TrashHandler) RestoreUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "無效的用戶ID")
+		return
+	}
+
+	var req RestoreUserRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "請求參數錯誤: "+err.Error())
+			return
+		}
+	}
+
+	user, err := h.trashService.RestoreUser(uint(id), req.Username, req.Email)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utils.ErrorResponse(c, http.StatusNotFound, "回收站中沒有此用戶")
+		case errors.Is(err, services.ErrUsernameTaken), errors.Is(err, services.ErrEmailTaken):
+			utils.ErrorResponse(c, http.StatusConflict, err.Error()+"，請指定新的值後再恢復")
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "恢復用戶失敗")
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, user)
+}
+
+// 永久刪除用戶
+func (h *TrashHandler) PurgeUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "無效的用戶ID")
+		return
+	}
+
+	if err := h.trashService.PurgeUser(c.Request.Context(), uint(id)); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utils.ErrorResponse(c, http.StatusNotFound, "回收站中沒有此用戶")
+		case errors.Is(err, services.ErrUserHasPosts):
+			utils.ErrorResponse(c, http.StatusConflict, err.Error()+"，請先刪除其文章")
+		default:
+			utils.ErrorResponse(c, http.StatusInternalServerError, "永久刪除用戶失敗")
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, gin.H{"message": "用戶已永久刪除"})
+}
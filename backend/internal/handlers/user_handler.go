@@ -1,13 +1,18 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
+	"backend/internal/models"
 	"backend/internal/services"
 	"backend/pkg/utils"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type UserHandler struct {
@@ -24,7 +29,13 @@ func NewUserHandler() *UserHandler {
 func (h *UserHandler) GetUsers(c *gin.Context) {
 	page, limit := utils.GetPaginationParams(c)
 
-	users, total, err := h.userService.GetUsers(page, limit)
+	filter, err := parseUserFilter(c.Request.URL.Query())
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	users, total, err := h.userService.GetUsers(page, limit, filter)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "獲取用戶列表失敗")
 		return
@@ -38,6 +49,29 @@ func (h *UserHandler) GetUsers(c *gin.Context) {
 	utils.PaginatedSuccessResponse(c, users, page, limit, total)
 }
 
+// 解析用戶篩選條件，列表查詢參數與批量操作的篩選表達式共用
+func parseUserFilter(query url.Values) (services.UserFilter, error) {
+	var filter services.UserFilter
+	var err error
+
+	filter.Role = query.Get("role")
+	if filter.Role != "" && !services.IsValidRole(filter.Role) {
+		return filter, services.ErrInvalidRole
+	}
+	filter.Status = query.Get("status")
+	if filter.Status != "" && filter.Status != models.UserStatusActive && filter.Status != models.UserStatusDisabled {
+		return filter, errors.New("status 只能是 active 或 disabled")
+	}
+	filter.UsernamePrefix = strings.TrimSpace(query.Get("username_prefix"))
+	if filter.CreatedFrom, err = parseTimeQuery(query, "created_from", false); err != nil {
+		return filter, err
+	}
+	if filter.CreatedTo, err = parseTimeQuery(query, "created_to", true); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
 // 根據 ID 獲取用戶
 func (h *UserHandler) GetUser(c *gin.Context) {
 	idStr := c.Param("id")
@@ -56,7 +90,7 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 	// 清除密碼字段
 	user.Password = ""
 
-	utils.SuccessResponse(c, user)
+	utils.ResourceResponse(c, user, user.Version, user.UpdatedAt)
 }
 
 // 創建用戶
@@ -95,7 +129,7 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 			"role":   req.Role,
 			"status": req.Status,
 		}
-		user, err = h.userService.UpdateUser(user.ID, updates)
+		user, err = h.userService.UpdateUser(user.ID, 0, updates)
 		if err != nil {
 			utils.ErrorResponse(c, http.StatusInternalServerError, "更新用戶信息失敗")
 			return
@@ -116,6 +150,9 @@ type UpdateUserRequest struct {
 	Status   string `json:"status"`
 	Avatar   string `json:"avatar"`
 	Password string `json:"password"`
+
+	// 客戶端持有的版本號，未提供 If-Match 請求頭時必填
+	Version *uint `json:"version"`
 }
 
 func (h *UserHandler) UpdateUser(c *gin.Context) {
@@ -132,6 +169,11 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 
+	version, ok := requestVersion(c, req.Version, true)
+	if !ok {
+		return
+	}
+
 	updates := make(map[string]interface{})
 
 	if req.Username != "" {
@@ -153,16 +195,28 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		updates["password"] = req.Password
 	}
 
-	user, err := h.userService.UpdateUser(uint(id), updates)
+	user, err := h.userService.UpdateUser(uint(id), version, updates)
 	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utils.ErrorResponse(c, http.StatusNotFound, "用戶不存在")
+		case errors.Is(err, services.ErrVersionConflict):
+			if current, err := h.userService.GetUserByID(uint(id)); err == nil {
+				current.Password = ""
+				respondVersionConflict(c, current, current.Version)
+				return
+			}
+			utils.ErrorResponse(c, http.StatusPreconditionFailed, err.Error())
+		default:
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		}
 		return
 	}
 
 	// 清除密碼字段
 	user.Password = ""
 
-	utils.SuccessResponse(c, user)
+	utils.ResourceResponse(c, user, user.Version, user.UpdatedAt)
 }
 
 // 刪除用戶
@@ -0,0 +1,98 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Scheduler 管理後台定時任務，停止時等待任務退出並執行收尾函數
+type Scheduler struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu    sync.Mutex
+	hooks []hook
+}
+
+type hook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+func NewScheduler() *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Scheduler{ctx: ctx, cancel: cancel}
+}
+
+// Every 按固定間隔執行任務，任務出錯只記錄日誌
+func (s *Scheduler) Every(name string, interval time.Duration, fn func(ctx context.Context) error) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+				run(s.ctx, name, fn)
+			}
+		}
+	}()
+}
+
+// Go 啟動一個長期運行的任務，ctx 在停止時取消
+func (s *Scheduler) Go(name string, fn func(ctx context.Context) error) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		run(s.ctx, name, fn)
+	}()
+}
+
+// OnStop 註冊停止時執行的收尾函數，按註冊順序執行
+func (s *Scheduler) OnStop(name string, fn func(ctx context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hooks = append(s.hooks, hook{name: name, fn: fn})
+}
+
+// Stop 停止所有任務並執行收尾函數，ctx 控制最長等待時間
+func (s *Scheduler) Stop(ctx context.Context) {
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		log.Println("等待後台任務退出超時")
+	}
+
+	s.mu.Lock()
+	hooks := s.hooks
+	s.mu.Unlock()
+	for _, h := range hooks {
+		run(ctx, h.name, h.fn)
+	}
+}
+
+// 執行任務，捕獲 panic 避免影響其他任務
+func run(ctx context.Context, name string, fn func(ctx context.Context) error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("任務 %s 異常: %v", name, r)
+		}
+	}()
+	if err := fn(ctx); err != nil && ctx.Err() == nil {
+		log.Printf("任務 %s 執行失敗: %v", name, err)
+	}
+}
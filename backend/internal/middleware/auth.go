@@ -4,8 +4,7 @@ import (
 	"net/http"
 	"strings"
 
-	"backend/internal/database"
-	"backend/internal/models"
+	"backend/internal/services"
 	"backend/pkg/utils"
 
 	"github.com/gin-gonic/gin"
@@ -38,9 +37,9 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		// 檢查用戶是否存在
-		var user models.User
-		if err := database.DB.First(&user, claims.UserID).Error; err != nil {
+		// 檢查用戶是否存在，用戶信息有緩存，避免每個請求都查詢數據庫
+		user, err := services.NewUserService().GetUserByID(claims.UserID)
+		if err != nil {
 			utils.ErrorResponse(c, http.StatusUnauthorized, "用戶不存在")
 			c.Abort()
 			return
@@ -55,9 +54,9 @@ func AuthMiddleware() gin.HandlerFunc {
 
 		// 設置用戶信息到上下文
 		c.Set("user_id", claims.UserID)
-		c.Set("username", claims.Username)
-		c.Set("role", claims.Role)
-		c.Set("user", user)
+		c.Set("username", user.Username)
+		c.Set("role", user.Role)
+		c.Set("user", *user)
 
 		c.Next()
 	}
@@ -75,3 +74,16 @@ func AdminMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// 審核者權限中間件（管理員與編輯）
+func ReviewerMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, exists := c.Get("role")
+		if !exists || (role != "admin" && role != "editor") {
+			utils.ErrorResponse(c, http.StatusForbidden, "需要審核權限")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 緩存控制中間件，為 GET/HEAD 請求設置 Cache-Control
+// 處理函數自行設置的 Cache-Control 優先；4xx、5xx 響應不允許緩存
+func CacheControlMiddleware(policy string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if policy == "" || (c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead) {
+			c.Next()
+			return
+		}
+
+		c.Writer = &cacheControlWriter{ResponseWriter: c.Writer, policy: policy}
+		c.Next()
+	}
+}
+
+// 在寫入響應頭之前按狀態碼設置 Cache-Control
+type cacheControlWriter struct {
+	gin.ResponseWriter
+	policy string
+	set    bool // Cache-Control 由本中間件設置
+}
+
+func (w *cacheControlWriter) apply(code int) {
+	if w.Written() {
+		return
+	}
+	header := w.Header()
+	if header.Get("Cache-Control") != "" && !w.set {
+		return
+	}
+	if code >= http.StatusBadRequest {
+		header.Set("Cache-Control", "no-store")
+	} else {
+		header.Set("Cache-Control", w.policy)
+	}
+	w.set = true
+}
+
+func (w *cacheControlWriter) WriteHeader(code int) {
+	w.apply(code)
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *cacheControlWriter) WriteHeaderNow() {
+	w.apply(w.Status())
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *cacheControlWriter) Write(data []byte) (int, error) {
+	w.apply(w.Status())
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *cacheControlWriter) WriteString(s string) (int, error) {
+	w.apply(w.Status())
+	return w.ResponseWriter.WriteString(s)
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"math"
 	"time"
 
 	"gorm.io/gorm"
@@ -17,19 +18,32 @@ type BaseModel struct {
 // 用戶模型
 type User struct {
 	BaseModel
-	Username string `json:"username" gorm:"uniqueIndex;not null;size:50"`
-	Email    string `json:"email" gorm:"uniqueIndex;not null;size:100"`
+	Username string `json:"username" gorm:"not null;size:50"`
+	Email    string `json:"email" gorm:"not null;size:100"`
 	Password string `json:"-" gorm:"not null"`
 	Role     string `json:"role" gorm:"default:user;size:20"`
 	Avatar   string `json:"avatar" gorm:"size:255"`
 	Status   string `json:"status" gorm:"default:active;size:20"`
 	Posts    []Post `json:"posts,omitempty" gorm:"foreignKey:AuthorID"`
+
+	// 上傳頭像生成的各尺寸地址，鍵為邊長
+	Avatars StringMap `json:"avatars,omitempty" gorm:"type:text"`
+
+	// 版本號，每次更新遞增，用於樂觀併發控制
+	Version uint `json:"version" gorm:"not null;default:1"`
 }
 
+// 用戶狀態
+const (
+	UserStatusActive   = "active"
+	UserStatusDisabled = "disabled"
+)
+
 // 文章模型
 type Post struct {
 	BaseModel
 	Title     string `json:"title" gorm:"not null;size:200"`
+	Slug      string `json:"slug" gorm:"size:120"`
 	Content   string `json:"content" gorm:"type:text"`
 	Summary   string `json:"summary" gorm:"size:500"`
 	Status    string `json:"status" gorm:"default:draft;size:20"`
@@ -37,6 +51,293 @@ type Post struct {
 	Author    User   `json:"author,omitempty" gorm:"foreignKey:AuthorID"`
 	Tags      []Tag  `json:"tags,omitempty" gorm:"many2many:post_tags;"`
 	ViewCount int    `json:"view_count" gorm:"default:0"`
+
+	CategoryID *uint     `json:"category_id" gorm:"index"`
+	Category   *Category `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
+
+	PublishedAt    *time.Time `json:"published_at"`
+	CommentsClosed bool       `json:"comments_closed" gorm:"default:false"`
+
+	// 可見範圍，與狀態相互獨立：狀態決定文章是否發布，可見範圍決定發布後誰能查看
+	Visibility   string     `json:"visibility" gorm:"size:20;not null;default:public;index"`
+	VisibleRoles StringList `json:"visible_roles,omitempty" gorm:"type:text"` // restricted 時可查看的角色
+	PasswordHash string     `json:"-" gorm:"size:100"`                        // password 時的訪問密碼
+
+	// 從其他系統導入時的原始標識，用於重複導入時匹配
+	ExternalID string `json:"external_id,omitempty" gorm:"size:200"`
+
+	// 版本號，每次更新遞增，用於樂觀併發控制
+	Version uint `json:"version" gorm:"not null;default:1"`
+
+	// 共同作者，即角色為 co_author 的協作者
+	CoAuthors []PostCollaborator `json:"co_authors,omitempty" gorm:"foreignKey:PostID"`
+
+	// 互動統計，僅在詳情中返回
+	Reactions     map[string]int64 `json:"reactions,omitempty" gorm:"-"`
+	MyReactions   []string         `json:"my_reactions,omitempty" gorm:"-"`
+	BookmarkCount int64            `json:"bookmark_count,omitempty" gorm:"-"`
+	Bookmarked    bool             `json:"bookmarked,omitempty" gorm:"-"`
+
+	// Markdown 渲染結果，僅在詳情中返回
+	Rendered *PostRender `json:"rendered,omitempty" gorm:"-"`
+
+	// 所屬系列與前後篇，僅在詳情中返回
+	Series *SeriesNav `json:"series,omitempty" gorm:"-"`
+}
+
+// 文章舊 slug，標題變更後用於跳轉
+type PostSlugRedirect struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Slug      string    `json:"slug" gorm:"uniqueIndex;not null;size:120"`
+	PostID    uint      `json:"post_id" gorm:"index;not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// 文章渲染緩存，按內容哈希區分版本
+type PostRender struct {
+	ID          uint      `json:"-" gorm:"primaryKey"`
+	PostID      uint      `json:"-" gorm:"uniqueIndex:idx_post_renders_post_hash;not null"`
+	ContentHash string    `json:"revision" gorm:"uniqueIndex:idx_post_renders_post_hash;size:64;not null"`
+	HTML        string    `json:"html" gorm:"type:text"`
+	TOC         TOC       `json:"toc" gorm:"type:text"`
+	WordCount   int       `json:"word_count"`
+	ReadingTime int       `json:"reading_time"` // 分鐘
+	CreatedAt   time.Time `json:"-"`
+}
+
+// 文章狀態
+const (
+	PostStatusDraft     = "draft"
+	PostStatusInReview  = "in_review"
+	PostStatusPublished = "published"
+	PostStatusArchived  = "archived"
+)
+
+// 文章可見範圍
+const (
+	PostVisibilityPublic     = "public"     // 所有人
+	PostVisibilityMembers    = "members"    // 登錄用戶
+	PostVisibilityRestricted = "restricted" // 指定角色與協作者
+	PostVisibilityPassword   = "password"   // 憑密碼解鎖
+)
+
+// 文章協作者角色
+const (
+	CollaboratorRoleCoAuthor = "co_author" // 共同作者：可編輯、變更狀態，列為作者之一
+	CollaboratorRoleEditor   = "editor"    // 編輯：可編輯
+	CollaboratorRoleViewer   = "viewer"    // 查看者：可查看未發布的文章
+)
+
+// 文章協作者
+type PostCollaborator struct {
+	ID        uint      `json:"-" gorm:"primaryKey"`
+	PostID    uint      `json:"post_id" gorm:"uniqueIndex:idx_post_collaborators_post_user;not null"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex:idx_post_collaborators_post_user;index;not null"`
+	User      *User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Role      string    `json:"role" gorm:"size:20;not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// 草稿預覽鏈接，持有令牌的訪客無需登錄即可只讀查看文章
+type PreviewToken struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	PostID    uint       `json:"post_id" gorm:"index;not null"`
+	CreatedBy uint       `json:"created_by" gorm:"index;not null"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+
+	// 簽名令牌與預覽地址，由 ID 與過期時間計算得出，不入庫
+	Token string `json:"token,omitempty" gorm:"-"`
+	URL   string `json:"url,omitempty" gorm:"-"`
+}
+
+// 文章狀態變更記錄
+type PostStatusHistory struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	PostID     uint      `json:"post_id" gorm:"index;not null"`
+	FromStatus string    `json:"from_status" gorm:"size:20"`
+	ToStatus   string    `json:"to_status" gorm:"size:20;not null"`
+	ActorID    uint      `json:"actor_id"`
+	Actor      *User     `json:"actor,omitempty" gorm:"foreignKey:ActorID"`
+	Comment    string    `json:"comment" gorm:"type:text"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// 評論狀態
+const (
+	CommentStatusPending  = "pending"
+	CommentStatusApproved = "approved"
+	CommentStatusRejected = "rejected"
+	CommentStatusSpam     = "spam"
+)
+
+// 評論模型
+type Comment struct {
+	BaseModel
+	PostID   uint   `json:"post_id" gorm:"index;not null"`
+	Post     *Post  `json:"post,omitempty" gorm:"foreignKey:PostID"`
+	UserID   *uint  `json:"user_id" gorm:"index"` // 導入的訪客評論沒有對應用戶，為 NULL
+	User     *User  `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	ParentID *uint  `json:"parent_id" gorm:"index"`
+	Content  string `json:"content" gorm:"type:text;not null"`
+	Status   string `json:"status" gorm:"default:pending;size:20;index"`
+	Deleted  bool   `json:"deleted,omitempty" gorm:"-"`
+
+	// 從其他系統導入的訪客評論保留原作者名
+	AuthorName string `json:"author_name,omitempty" gorm:"size:100"`
+	ExternalID string `json:"-" gorm:"size:100;index"`
+
+	Replies []*Comment `json:"replies,omitempty" gorm:"-"`
+}
+
+// 文章表情回應
+type Reaction struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	PostID    uint      `json:"post_id" gorm:"uniqueIndex:idx_reactions_post_user_type;not null"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex:idx_reactions_post_user_type;index;not null"`
+	Type      string    `json:"type" gorm:"uniqueIndex:idx_reactions_post_user_type;size:20;not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// 文章收藏
+type Bookmark struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	PostID    uint      `json:"post_id" gorm:"uniqueIndex:idx_bookmarks_post_user;not null"`
+	Post      *Post     `json:"post,omitempty" gorm:"foreignKey:PostID"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex:idx_bookmarks_post_user;index;not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// 文章瀏覽事件，匯總到每日統計後刪除
+type PostViewEvent struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	PostID      uint      `json:"post_id" gorm:"index;not null"`
+	Date        string    `json:"date" gorm:"index;size:10;not null"` // UTC 日期 YYYY-MM-DD
+	VisitorHash string    `json:"-" gorm:"size:64;not null"`          // 按日加鹽的匿名訪客標識
+	Referrer    string    `json:"referrer" gorm:"size:255"`           // 來源域名，直接訪問為空
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// 文章每日瀏覽統計
+type PostDailyStat struct {
+	ID       uint   `json:"-" gorm:"primaryKey"`
+	PostID   uint   `json:"post_id" gorm:"uniqueIndex:idx_post_daily_stats_post_date;not null"`
+	Date     string `json:"date" gorm:"uniqueIndex:idx_post_daily_stats_post_date;index;size:10;not null"`
+	Views    int64  `json:"views"`
+	Visitors int64  `json:"visitors"`
+}
+
+// 已匯總日期的獨立訪客，遲到的瀏覽事件匯總時據此去重
+type PostDailyVisitor struct {
+	ID          uint   `json:"-" gorm:"primaryKey"`
+	PostID      uint   `json:"post_id" gorm:"uniqueIndex:idx_post_daily_visitors_post_date_visitor;not null"`
+	Date        string `json:"date" gorm:"uniqueIndex:idx_post_daily_visitors_post_date_visitor;index;size:10;not null"`
+	VisitorHash string `json:"-" gorm:"uniqueIndex:idx_post_daily_visitors_post_date_visitor;size:64;not null"`
+}
+
+// 文章每日來源統計
+type PostReferrerStat struct {
+	ID     uint   `json:"-" gorm:"primaryKey"`
+	PostID uint   `json:"post_id" gorm:"uniqueIndex:idx_post_referrer_stats_post_date_domain;not null"`
+	Date   string `json:"date" gorm:"uniqueIndex:idx_post_referrer_stats_post_date_domain;index;size:10;not null"`
+	Domain string `json:"domain" gorm:"uniqueIndex:idx_post_referrer_stats_post_date_domain;size:255"`
+	Views  int64  `json:"views"`
+}
+
+// 文章詞頻，用於計算相關文章的文本相似度
+type PostTerm struct {
+	ID     uint   `json:"-" gorm:"primaryKey"`
+	PostID uint   `json:"post_id" gorm:"uniqueIndex:idx_post_terms_post_term;not null"`
+	Term   string `json:"term" gorm:"uniqueIndex:idx_post_terms_post_term;index;size:64;not null"`
+	Count  int    `json:"count"`
+}
+
+// 文章文本索引狀態，文章在 IndexedAt 之後更新時重新計算
+type PostTextIndex struct {
+	PostID         uint      `json:"post_id" gorm:"primaryKey;autoIncrement:false"`
+	Norm           float64   `json:"norm"`                                      // TF-IDF 向量長度
+	IndexedVersion uint      `json:"indexed_version" gorm:"not null;default:0"` // 索引時的文章版本號，與文章當前版本不同時重新索引
+	IndexedAt      time.Time `json:"indexed_at"`
+}
+
+// 預計算的文章文本相似度，每篇文章只保留得分最高的若干篇
+type PostSimilarity struct {
+	ID        uint    `json:"-" gorm:"primaryKey"`
+	PostID    uint    `json:"post_id" gorm:"uniqueIndex:idx_post_similarities_post_related;not null"`
+	RelatedID uint    `json:"related_id" gorm:"uniqueIndex:idx_post_similarities_post_related;index;not null"`
+	Score     float64 `json:"score"`
+}
+
+// 媒體文件訪問路徑前綴
+const MediaURLPrefix = "/media/files/"
+
+// 媒體文件模型
+type Media struct {
+	BaseModel
+	UserID     uint   `json:"user_id" gorm:"index;not null"`
+	User       *User  `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Filename   string `json:"filename" gorm:"size:255"`
+	StorageKey string `json:"key" gorm:"uniqueIndex;not null;size:255"`
+	Backend    string `json:"backend" gorm:"size:20"`
+	MimeType   string `json:"mime_type" gorm:"size:100"`
+	Size       int64  `json:"size"`
+	Purpose    string `json:"purpose" gorm:"default:library;size:20;index"`
+	URL        string `json:"url" gorm:"-"`
+}
+
+// 媒體用途
+const (
+	MediaPurposeLibrary = "library"
+	MediaPurposeAvatar  = "avatar"
+)
+
+// 查詢後填充訪問地址
+func (m *Media) AfterFind(tx *gorm.DB) error {
+	m.URL = MediaURLPrefix + m.StorageKey
+	return nil
+}
+
+// 系列模型，將多篇文章按順序組織在一起
+type Series struct {
+	BaseModel
+	Title       string `json:"title" gorm:"not null;size:200"`
+	Description string `json:"description" gorm:"type:text"`
+	AuthorID    uint   `json:"author_id" gorm:"index;not null"`
+	Author      *User  `json:"author,omitempty" gorm:"foreignKey:AuthorID"`
+
+	// 按順序排列的文章，僅在詳情中返回
+	Posts []SeriesPost `json:"posts,omitempty" gorm:"foreignKey:SeriesID"`
+
+	// 已發布的文章數，僅在列表中返回
+	PostCount int64 `json:"post_count" gorm:"-"`
+}
+
+// 系列中的文章，一篇文章只能屬於一個系列
+type SeriesPost struct {
+	ID       uint  `json:"-" gorm:"primaryKey"`
+	SeriesID uint  `json:"series_id" gorm:"index;not null"`
+	PostID   uint  `json:"post_id" gorm:"uniqueIndex;not null"`
+	Post     *Post `json:"post,omitempty" gorm:"foreignKey:PostID"`
+	Position int   `json:"position" gorm:"not null"` // 從 1 開始
+}
+
+// 文章所屬系列的導航信息，位置與總數只計算讀者可見的文章
+type SeriesNav struct {
+	ID       uint           `json:"id"`
+	Title    string         `json:"title"`
+	Position int            `json:"position"`
+	Total    int            `json:"total"`
+	Prev     *SeriesNavPost `json:"prev"`
+	Next     *SeriesNavPost `json:"next"`
+}
+
+// 系列導航中的相鄰文章
+type SeriesNavPost struct {
+	ID    uint   `json:"id"`
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
 }
 
 // 標籤模型
@@ -79,3 +380,39 @@ type Log struct {
 	Method    string    `json:"method" gorm:"size:10"`
 	CreatedAt time.Time `json:"created_at"`
 }
+
+// 導入任務狀態
+const (
+	ImportJobPending   = "pending"
+	ImportJobRunning   = "running"
+	ImportJobCompleted = "completed"
+	ImportJobFailed    = "failed"
+)
+
+// 導入任務，記錄後台導入的進度與結果
+type ImportJob struct {
+	BaseModel
+	UserID     uint        `json:"user_id" gorm:"index;not null"`
+	Source     string      `json:"source" gorm:"size:20"`
+	Filename   string      `json:"filename" gorm:"size:255"`
+	FilePath   string      `json:"-" gorm:"size:500"`
+	Status     string      `json:"status" gorm:"default:pending;size:20;index"`
+	BytesTotal int64       `json:"bytes_total"`
+	BytesRead  int64       `json:"bytes_read"`
+	Progress   float64     `json:"progress" gorm:"-"`
+	Report     ImportStats `json:"report" gorm:"type:text"`
+	Error      string      `json:"error,omitempty" gorm:"type:text"`
+	StartedAt  *time.Time  `json:"started_at"`
+	FinishedAt *time.Time  `json:"finished_at"`
+}
+
+// 計算進度百分比
+func (j *ImportJob) AfterFind(tx *gorm.DB) error {
+	switch {
+	case j.Status == ImportJobCompleted:
+		j.Progress = 100
+	case j.BytesTotal > 0:
+		j.Progress = math.Floor(float64(j.BytesRead)*1000/float64(j.BytesTotal)) / 10
+	}
+	return nil
+}
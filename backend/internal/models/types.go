@@ -0,0 +1,123 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// 目錄項
+type TOCEntry struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	ID    string `json:"id"`
+}
+
+// 文章目錄，以 JSON 存儲
+type TOC []TOCEntry
+
+func (t TOC) Value() (driver.Value, error) {
+	if t == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(t)
+	return string(data), err
+}
+
+func (t *TOC) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*t = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), t)
+	case []byte:
+		return json.Unmarshal(v, t)
+	}
+	return errors.New("無法解析目錄數據")
+}
+
+// 字符串映射，以 JSON 存儲
+type StringMap map[string]string
+
+func (m StringMap) Value() (driver.Value, error) {
+	if m == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(m)
+	return string(data), err
+}
+
+func (m *StringMap) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), m)
+	case []byte:
+		return json.Unmarshal(v, m)
+	}
+	return errors.New("無法解析映射數據")
+}
+
+// 字符串列表，以 JSON 存儲
+type StringList []string
+
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(l)
+	return string(data), err
+}
+
+func (l *StringList) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	case []byte:
+		return json.Unmarshal(v, l)
+	}
+	return errors.New("無法解析列表數據")
+}
+
+// 導入錯誤
+type ImportError struct {
+	Item  string `json:"item"`
+	Error string `json:"error"`
+}
+
+// 導入統計，以 JSON 存儲
+type ImportStats struct {
+	Authors    int           `json:"authors"`
+	Categories int           `json:"categories"`
+	Tags       int           `json:"tags"`
+	Created    int           `json:"created"`
+	Updated    int           `json:"updated"`
+	Comments   int           `json:"comments"`
+	Skipped    int           `json:"skipped"`
+	Failed     int           `json:"failed"`
+	Errors     []ImportError `json:"errors"`
+}
+
+func (s ImportStats) Value() (driver.Value, error) {
+	data, err := json.Marshal(s)
+	return string(data), err
+}
+
+func (s *ImportStats) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*s = ImportStats{}
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	case []byte:
+		return json.Unmarshal(v, s)
+	}
+	return errors.New("無法解析導入統計")
+}
@@ -1,6 +1,7 @@
 package router
 
 import (
+	"backend/config"
 	"backend/internal/middleware"
 
 	"github.com/gin-gonic/gin"
@@ -9,6 +10,7 @@ import (
 // setupAdminRoutes 設置管理員路由
 func (r *Router) setupAdminRoutes(api *gin.RouterGroup) {
 	admin := api.Group("/admin")
+	admin.Use(middleware.CacheControlMiddleware(config.AppConfig.CacheControlAdmin))
 	admin.Use(middleware.AuthMiddleware())
 	admin.Use(middleware.AdminMiddleware())
 	{
@@ -17,6 +19,18 @@ func (r *Router) setupAdminRoutes(api *gin.RouterGroup) {
 
 		// 文章管理路由
 		r.setupAdminPostRoutes(admin)
+
+		// 評論審核路由
+		r.setupAdminCommentRoutes(admin)
+
+		// 回收站路由
+		r.setupAdminTrashRoutes(admin)
+
+		// 導入任務路由
+		r.setupAdminImportRoutes(admin)
+
+		// 緩存統計
+		admin.GET("/cache/stats", r.cacheHandler.GetStats)
 	}
 }
 
@@ -29,6 +43,7 @@ func (r *Router) setupAdminUserRoutes(admin *gin.RouterGroup) {
 		adminUsers.POST("", r.userHandler.CreateUser)
 		adminUsers.PUT("/:id", r.userHandler.UpdateUser)
 		adminUsers.DELETE("/:id", r.userHandler.DeleteUser)
+		adminUsers.POST("/bulk", r.bulkHandler.BulkUsers)
 	}
 }
 
@@ -40,5 +55,40 @@ func (r *Router) setupAdminPostRoutes(admin *gin.RouterGroup) {
 		adminPosts.GET("/:id", r.postHandler.GetPost)
 		adminPosts.PUT("/:id", r.postHandler.UpdatePost)
 		adminPosts.DELETE("/:id", r.postHandler.DeletePost)
+		adminPosts.POST("/bulk", r.bulkHandler.BulkPosts)
+		adminPosts.GET("/export", r.postHandler.ExportPosts)
+		adminPosts.POST("/import", r.postHandler.ImportPosts)
+	}
+}
+
+// setupAdminCommentRoutes 設置管理員評論審核路由
+func (r *Router) setupAdminCommentRoutes(admin *gin.RouterGroup) {
+	adminComments := admin.Group("/comments")
+	{
+		adminComments.GET("", r.commentHandler.GetModerationQueue)
+		adminComments.PUT("/:id/status", r.commentHandler.ModerateComment)
+	}
+}
+
+// setupAdminTrashRoutes 設置管理員回收站路由
+func (r *Router) setupAdminTrashRoutes(admin *gin.RouterGroup) {
+	trash := admin.Group("/trash")
+	{
+		trash.GET("/posts", r.trashHandler.GetDeletedPosts)
+		trash.POST("/posts/:id/restore", r.trashHandler.RestorePost)
+		trash.DELETE("/posts/:id", r.trashHandler.PurgePost)
+		trash.GET("/users", r.trashHandler.GetDeletedUsers)
+		trash.POST("/users/:id/restore", r.trashHandler.RestoreUser)
+		trash.DELETE("/users/:id", r.trashHandler.PurgeUser)
+	}
+}
+
+// setupAdminImportRoutes 設置管理員導入任務路由
+func (r *Router) setupAdminImportRoutes(admin *gin.RouterGroup) {
+	imports := admin.Group("/imports")
+	{
+		imports.GET("", r.importHandler.GetJobs)
+		imports.GET("/:id", r.importHandler.GetJob)
+		imports.POST("/wordpress", r.importHandler.ImportWordPress)
 	}
 }
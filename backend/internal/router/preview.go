@@ -0,0 +1,10 @@
+package router
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// setupPreviewRoutes 設置草稿預覽路由（不需要登錄，憑簽名令牌訪問）
+func (r *Router) setupPreviewRoutes(api *gin.RouterGroup) {
+	api.GET("/preview/:token", r.previewHandler.ViewPreview)
+}
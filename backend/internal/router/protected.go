@@ -1,6 +1,7 @@
 package router
 
 import (
+	"backend/config"
 	"backend/internal/middleware"
 
 	"github.com/gin-gonic/gin"
@@ -10,6 +11,7 @@ import (
 func (r *Router) setupProtectedRoutes(api *gin.RouterGroup) {
 	// 需要認證的路由
 	protected := api.Group("/")
+	protected.Use(middleware.CacheControlMiddleware(config.AppConfig.CacheControlPrivate))
 	protected.Use(middleware.AuthMiddleware())
 	{
 		// 用戶相關路由
@@ -17,6 +19,15 @@ func (r *Router) setupProtectedRoutes(api *gin.RouterGroup) {
 
 		// 文章相關路由
 		r.setupPostRoutes(protected)
+
+		// 評論相關路由
+		r.setupCommentRoutes(protected)
+
+		// 系列相關路由
+		r.setupSeriesRoutes(protected)
+
+		// 媒體庫路由
+		r.setupMediaRoutes(protected)
 	}
 }
 
@@ -26,7 +37,9 @@ func (r *Router) setupUserRoutes(protected *gin.RouterGroup) {
 	{
 		user.GET("/profile", r.authHandler.Profile)
 		user.PUT("/profile", r.authHandler.UpdateProfile)
+		user.POST("/avatar", r.authHandler.UploadAvatar)
 		user.POST("/change-password", r.authHandler.ChangePassword)
+		user.GET("/bookmarks", r.bookmarkHandler.GetBookmarks)
 	}
 }
 
@@ -36,10 +49,81 @@ func (r *Router) setupPostRoutes(protected *gin.RouterGroup) {
 	{
 		posts.GET("", r.postHandler.GetPosts)
 		posts.GET("/my", r.postHandler.GetMyPosts)
+		posts.GET("/my/stats", r.analyticsHandler.GetAuthorStats)
 		posts.GET("/search", r.postHandler.SearchPosts)
+		posts.GET("/slug/:slug", r.postHandler.GetPostBySlug)
 		posts.GET("/:id", r.postHandler.GetPost)
+		posts.GET("/:id/related", r.relatedHandler.GetRelatedPosts)
 		posts.POST("", r.postHandler.CreatePost)
 		posts.PUT("/:id", r.postHandler.UpdatePost)
 		posts.DELETE("/:id", r.postHandler.DeletePost)
+
+		// 狀態流轉與審核
+		posts.POST("/:id/status", r.postHandler.ChangePostStatus)
+		posts.GET("/:id/status-history", r.postHandler.GetPostStatusHistory)
+		posts.GET("/:id/stats", r.analyticsHandler.GetPostStats)
+		posts.PUT("/:id/visibility", r.postHandler.SetVisibility)
+		posts.POST("/:id/unlock", r.postHandler.UnlockPost)
+		posts.POST("/:id/approve", middleware.ReviewerMiddleware(), r.postHandler.ApprovePost)
+		posts.POST("/:id/reject", middleware.ReviewerMiddleware(), r.postHandler.RejectPost)
+
+		// 協作者
+		posts.GET("/:id/collaborators", r.collaboratorHandler.GetCollaborators)
+		posts.PUT("/:id/collaborators/:user_id", r.collaboratorHandler.SetCollaborator)
+		posts.DELETE("/:id/collaborators/:user_id", r.collaboratorHandler.RemoveCollaborator)
+
+		// 草稿預覽鏈接
+		posts.GET("/:id/previews", r.previewHandler.GetPreviews)
+		posts.POST("/:id/previews", r.previewHandler.CreatePreview)
+		posts.DELETE("/:id/previews/:preview_id", r.previewHandler.RevokePreview)
+
+		// 文章評論
+		posts.GET("/:id/comments", r.commentHandler.GetComments)
+		posts.POST("/:id/comments", r.commentHandler.CreateComment)
+		posts.PUT("/:id/comments/closed", r.commentHandler.SetCommentsClosed)
+
+		// 表情回應與收藏
+		posts.GET("/reaction-types", r.reactionHandler.GetReactionTypes)
+		posts.PUT("/:id/reactions/:type", r.reactionHandler.AddReaction)
+		posts.DELETE("/:id/reactions/:type", r.reactionHandler.RemoveReaction)
+		posts.PUT("/:id/bookmark", r.bookmarkHandler.AddBookmark)
+		posts.DELETE("/:id/bookmark", r.bookmarkHandler.RemoveBookmark)
+	}
+}
+
+// setupSeriesRoutes 設置系列相關路由
+func (r *Router) setupSeriesRoutes(protected *gin.RouterGroup) {
+	series := protected.Group("/series")
+	{
+		series.GET("", r.seriesHandler.GetSeriesList)
+		series.POST("", r.seriesHandler.CreateSeries)
+		series.GET("/:id", r.seriesHandler.GetSeries)
+		series.PUT("/:id", r.seriesHandler.UpdateSeries)
+		series.DELETE("/:id", r.seriesHandler.DeleteSeries)
+		series.POST("/:id/posts", r.seriesHandler.AddPost)
+		series.PUT("/:id/posts", r.seriesHandler.ReorderPosts)
+		series.DELETE("/:id/posts/:post_id", r.seriesHandler.RemovePost)
+	}
+}
+
+// setupCommentRoutes 設置評論相關路由
+func (r *Router) setupCommentRoutes(protected *gin.RouterGroup) {
+	comments := protected.Group("/comments")
+	{
+		comments.PUT("/:id", r.commentHandler.UpdateComment)
+		comments.DELETE("/:id", r.commentHandler.DeleteComment)
+	}
+}
+
+// setupMediaRoutes 設置媒體庫路由
+func (r *Router) setupMediaRoutes(protected *gin.RouterGroup) {
+	media := protected.Group("/media")
+	{
+		media.GET("", r.mediaHandler.GetMediaList)
+		media.POST("", r.mediaHandler.Upload)
+		media.GET("/usage", r.mediaHandler.GetUsage)
+		media.GET("/:id", r.mediaHandler.GetMedia)
+		media.GET("/:id/url", r.mediaHandler.GetSignedURL)
+		media.DELETE("/:id", r.mediaHandler.DeleteMedia)
 	}
 }
@@ -0,0 +1,30 @@
+package router
+
+import (
+	"backend/config"
+	"backend/internal/handlers"
+	"backend/internal/middleware"
+)
+
+// setupPublicRoutes 設置不在 /api 下的公開路由（無需登錄，響應可被 CDN 緩存）
+func (r *Router) setupPublicRoutes() {
+	public := r.engine.Group("/")
+	public.Use(middleware.CacheControlMiddleware(config.AppConfig.CacheControlPublic))
+
+	// 媒體文件
+	public.GET("/media/files/*key", r.mediaHandler.ServeFile)
+
+	// 訂閱源：全站、作者、標籤
+	for _, format := range []string{handlers.FeedFormatRSS, handlers.FeedFormatAtom, handlers.FeedFormatJSON} {
+		public.GET("/feed."+format, r.feedHandler.SiteFeed(format))
+		public.GET("/authors/:username/feed."+format, r.feedHandler.AuthorFeed(format))
+		public.GET("/tags/:tag/feed."+format, r.feedHandler.TagFeed(format))
+	}
+
+	// 站點地圖與服務端渲染頁面
+	public.GET("/sitemap.xml", r.seoHandler.Sitemap)
+	public.GET("/sitemaps/:name", r.seoHandler.SitemapFile)
+	public.GET("/posts/:slug", r.seoHandler.PostPage)
+	public.GET("/categories/:name", r.seoHandler.CategoryPage)
+	public.GET("/tags/:tag", r.seoHandler.TagPage)
+}
@@ -9,19 +9,49 @@ import (
 
 // Router 路由結構體
 type Router struct {
-	engine      *gin.Engine
-	authHandler *handlers.AuthHandler
-	userHandler *handlers.UserHandler
-	postHandler *handlers.PostHandler
+	engine              *gin.Engine
+	authHandler         *handlers.AuthHandler
+	userHandler         *handlers.UserHandler
+	postHandler         *handlers.PostHandler
+	commentHandler      *handlers.CommentHandler
+	reactionHandler     *handlers.ReactionHandler
+	bookmarkHandler     *handlers.BookmarkHandler
+	mediaHandler        *handlers.MediaHandler
+	feedHandler         *handlers.FeedHandler
+	seoHandler          *handlers.SEOHandler
+	analyticsHandler    *handlers.AnalyticsHandler
+	trashHandler        *handlers.TrashHandler
+	bulkHandler         *handlers.BulkHandler
+	importHandler       *handlers.ImportHandler
+	cacheHandler        *handlers.CacheHandler
+	collaboratorHandler *handlers.CollaboratorHandler
+	seriesHandler       *handlers.SeriesHandler
+	previewHandler      *handlers.PreviewHandler
+	relatedHandler      *handlers.RelatedHandler
 }
 
 // NewRouter 創建新的路由實例
 func NewRouter() *Router {
 	return &Router{
-		engine:      gin.New(),
-		authHandler: handlers.NewAuthHandler(),
-		userHandler: handlers.NewUserHandler(),
-		postHandler: handlers.NewPostHandler(),
+		engine:              gin.New(),
+		authHandler:         handlers.NewAuthHandler(),
+		userHandler:         handlers.NewUserHandler(),
+		postHandler:         handlers.NewPostHandler(),
+		commentHandler:      handlers.NewCommentHandler(),
+		reactionHandler:     handlers.NewReactionHandler(),
+		bookmarkHandler:     handlers.NewBookmarkHandler(),
+		mediaHandler:        handlers.NewMediaHandler(),
+		feedHandler:         handlers.NewFeedHandler(),
+		seoHandler:          handlers.NewSEOHandler(),
+		analyticsHandler:    handlers.NewAnalyticsHandler(),
+		trashHandler:        handlers.NewTrashHandler(),
+		bulkHandler:         handlers.NewBulkHandler(),
+		importHandler:       handlers.NewImportHandler(),
+		cacheHandler:        handlers.NewCacheHandler(),
+		collaboratorHandler: handlers.NewCollaboratorHandler(),
+		seriesHandler:       handlers.NewSeriesHandler(),
+		previewHandler:      handlers.NewPreviewHandler(),
+		relatedHandler:      handlers.NewRelatedHandler(),
 	}
 }
 
@@ -45,8 +75,12 @@ func (r *Router) SetupRoutes() {
 	// 設置各個路由組
 	r.setupHealthRoutes(api)
 	r.setupAuthRoutes(api)
+	r.setupPreviewRoutes(api)
 	r.setupProtectedRoutes(api)
 	r.setupAdminRoutes(api)
+
+	// 非 API 的公開路由
+	r.setupPublicRoutes()
 }
 
 // Initialize 初始化路由器
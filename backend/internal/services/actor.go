@@ -0,0 +1,29 @@
+package services
+
+// 角色
+const (
+	RoleUser   = "user"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// IsValidRole 檢查角色是否合法
+func IsValidRole(role string) bool {
+	return role == RoleUser || role == RoleEditor || role == RoleAdmin
+}
+
+// Actor 執行操作的用戶
+type Actor struct {
+	UserID uint
+	Role   string
+}
+
+// IsAdmin 是否為管理員
+func (a Actor) IsAdmin() bool {
+	return a.Role == RoleAdmin
+}
+
+// IsReviewer 是否具有審核權限（管理員與編輯）
+func (a Actor) IsReviewer() bool {
+	return a.Role == RoleAdmin || a.Role == RoleEditor
+}
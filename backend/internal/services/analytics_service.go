@@ -0,0 +1,393 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"backend/config"
+	"backend/internal/database"
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 統計日期格式，均為 UTC
+const StatsDateFormat = "2006-01-02"
+
+// 單次查詢的最大天數
+const MaxStatsDays = 366
+
+// 來源排行返回的條目數
+const topReferrersLimit = 20
+
+// 已匯總日期的訪客記錄保留天數，瀏覽事件最遲在次日寫入，保留期足以覆蓋遲到的事件
+const dailyVisitorRetentionDays = 7
+
+// 尚未計入訪客表的訪客，與訪客表關聯後去重
+const (
+	newVisitorsJoin  = "LEFT JOIN post_daily_visitors AS v ON v.post_id = post_view_events.post_id AND v.date = post_view_events.date AND v.visitor_hash = post_view_events.visitor_hash"
+	newVisitorsCount = "COUNT(DISTINCT CASE WHEN v.id IS NULL THEN post_view_events.visitor_hash END)"
+)
+
+var ErrInvalidStatsRange = errors.New("無效的統計日期範圍")
+
+// 每日統計
+type DailyStats struct {
+	Date     string `json:"date"`
+	Views    int64  `json:"views"`
+	Visitors int64  `json:"visitors"`
+}
+
+// 來源統計
+type ReferrerStats struct {
+	Domain string `json:"domain"`
+	Views  int64  `json:"views"`
+}
+
+// 文章瀏覽統計
+type PostStats struct {
+	PostID    uint            `json:"post_id"`
+	From      string          `json:"from"`
+	To        string          `json:"to"`
+	Views     int64           `json:"views"`
+	Visitors  int64           `json:"visitors"` // 各日獨立訪客之和
+	Daily     []DailyStats    `json:"daily"`
+	Referrers []ReferrerStats `json:"referrers"`
+}
+
+// 文章排行
+type TopPost struct {
+	PostID uint   `json:"post_id"`
+	Title  string `json:"title"`
+	Views  int64  `json:"views"`
+}
+
+// 作者數據面板
+type AuthorStats struct {
+	AuthorID  uint            `json:"author_id"`
+	From      string          `json:"from"`
+	To        string          `json:"to"`
+	Views     int64           `json:"views"`
+	Visitors  int64           `json:"visitors"`
+	Daily     []DailyStats    `json:"daily"`
+	TopPosts  []TopPost       `json:"top_posts"`
+	Referrers []ReferrerStats `json:"referrers"`
+}
+
+type AnalyticsService struct{}
+
+func NewAnalyticsService() *AnalyticsService {
+	return &AnalyticsService{}
+}
+
+// VisitorHash 匿名訪客標識：以按日變化的鹽對訪客做 HMAC，不同日期之間無法關聯
+func VisitorHash(viewer string, date string) string {
+	salt := hmacSHA256Hex(config.AppConfig.JWTSecret, "visitor-salt:"+date)
+	return hmacSHA256Hex(salt, viewer)[:32]
+}
+
+func hmacSHA256Hex(key, data string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(data))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ReferrerDomain 提取來源域名，站內跳轉與無法解析的地址返回空字符串
+func ReferrerDomain(referrer string) string {
+	u, err := url.Parse(strings.TrimSpace(referrer))
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	host := strings.ToLower(u.Hostname())
+	if site, err := url.Parse(config.AppConfig.SiteURL); err == nil && strings.EqualFold(site.Hostname(), host) {
+		return ""
+	}
+	if ip := net.ParseIP(host); ip == nil {
+		host = strings.TrimPrefix(host, "www.")
+	}
+	if len(host) > 255 {
+		host = host[:255]
+	}
+	return host
+}
+
+// 保存瀏覽事件
+func (s *AnalyticsService) SaveEvents(events []models.PostViewEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return database.DB.CreateInBatches(events, 500).Error
+}
+
+// RollupEvents 將已結束日期的瀏覽事件匯總到每日統計並刪除原始事件
+func (s *AnalyticsService) RollupEvents() error {
+	today := time.Now().UTC().Format(StatsDateFormat)
+
+	var dates []string
+	if err := database.DB.Model(&models.PostViewEvent{}).Where("date < ?", today).
+		Distinct("date").Order("date").Pluck("date", &dates).Error; err != nil {
+		return err
+	}
+
+	for _, date := range dates {
+		if err := s.rollupDate(date); err != nil {
+			return err
+		}
+	}
+
+	cutoff := time.Now().UTC().AddDate(0, 0, -dailyVisitorRetentionDays).Format(StatsDateFormat)
+	return database.DB.Where("date < ?", cutoff).Delete(&models.PostDailyVisitor{}).Error
+}
+
+// 匯總某一天的事件，累加到已有統計上
+// 該日已匯總過時（遲到的事件），只累加訪客表中沒有的訪客，避免重複計算獨立訪客
+func (s *AnalyticsService) rollupDate(date string) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		var daily []models.PostDailyStat
+		if err := tx.Model(&models.PostViewEvent{}).Joins(newVisitorsJoin).
+			Where("post_view_events.date = ?", date).
+			Select("post_view_events.post_id, post_view_events.date, COUNT(*) AS views, " + newVisitorsCount + " AS visitors").
+			Group("post_view_events.post_id, post_view_events.date").Scan(&daily).Error; err != nil {
+			return err
+		}
+		for _, stat := range daily {
+			err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "post_id"}, {Name: "date"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{
+					"views":    gorm.Expr("post_daily_stats.views + excluded.views"),
+					"visitors": gorm.Expr("post_daily_stats.visitors + excluded.visitors"),
+				}),
+			}).Create(&stat).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		var referrers []models.PostReferrerStat
+		if err := tx.Model(&models.PostViewEvent{}).Where("date = ?", date).
+			Select("post_id, date, referrer AS domain, COUNT(*) AS views").
+			Group("post_id, date, referrer").Scan(&referrers).Error; err != nil {
+			return err
+		}
+		for _, stat := range referrers {
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "post_id"}, {Name: "date"}, {Name: "domain"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{"views": gorm.Expr("post_referrer_stats.views + excluded.views")}),
+			}).Create(&stat).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Exec(`INSERT INTO post_daily_visitors (post_id, date, visitor_hash)
+			SELECT DISTINCT post_id, date, visitor_hash FROM post_view_events WHERE date = ?
+			ON CONFLICT DO NOTHING`, date).Error; err != nil {
+			return err
+		}
+		return tx.Where("date = ?", date).Delete(&models.PostViewEvent{}).Error
+	})
+}
+
+// ParseStatsRange 解析日期範圍，默認最近 30 天
+func ParseStatsRange(from, to string) (string, string, error) {
+	end := time.Now().UTC()
+	if to != "" {
+		t, err := time.Parse(StatsDateFormat, to)
+		if err != nil {
+			return "", "", ErrInvalidStatsRange
+		}
+		end = t
+	}
+	start := end.AddDate(0, 0, -29)
+	if from != "" {
+		t, err := time.Parse(StatsDateFormat, from)
+		if err != nil {
+			return "", "", ErrInvalidStatsRange
+		}
+		start = t
+	}
+	if start.After(end) || end.Sub(start) >= MaxStatsDays*24*time.Hour {
+		return "", "", ErrInvalidStatsRange
+	}
+	return start.Format(StatsDateFormat), end.Format(StatsDateFormat), nil
+}
+
+// 每日統計：已匯總的數據加上尚未匯總的事件（含當天）
+func (s *AnalyticsService) dailyStats(postIDs []uint, from, to string) ([]DailyStats, error) {
+	var rolled []DailyStats
+	if err := database.DB.Model(&models.PostDailyStat{}).
+		Where("post_id IN ? AND date BETWEEN ? AND ?", postIDs, from, to).
+		Select("date, SUM(views) AS views, SUM(visitors) AS visitors").
+		Group("date").Scan(&rolled).Error; err != nil {
+		return nil, err
+	}
+
+	// 原始事件按文章分組統計獨立訪客，與匯總表口徑一致，已計入匯總的訪客不重複計算
+	var live []DailyStats
+	if err := database.DB.Table("(?) AS t", database.DB.Model(&models.PostViewEvent{}).Joins(newVisitorsJoin).
+		Where("post_view_events.post_id IN ? AND post_view_events.date BETWEEN ? AND ?", postIDs, from, to).
+		Select("post_view_events.post_id, post_view_events.date, COUNT(*) AS views, "+newVisitorsCount+" AS visitors").
+		Group("post_view_events.post_id, post_view_events.date")).
+		Select("date, SUM(views) AS views, SUM(visitors) AS visitors").
+		Group("date").Scan(&live).Error; err != nil {
+		return nil, err
+	}
+
+	byDate := make(map[string]*DailyStats)
+	for _, list := range [][]DailyStats{rolled, live} {
+		for _, d := range list {
+			if existing, ok := byDate[d.Date]; ok {
+				existing.Views += d.Views
+				existing.Visitors += d.Visitors
+			} else {
+				d := d
+				byDate[d.Date] = &d
+			}
+		}
+	}
+
+	// 補齊沒有瀏覽的日期
+	start, _ := time.Parse(StatsDateFormat, from)
+	end, _ := time.Parse(StatsDateFormat, to)
+	var daily []DailyStats
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format(StatsDateFormat)
+		if d, ok := byDate[date]; ok {
+			daily = append(daily, *d)
+		} else {
+			daily = append(daily, DailyStats{Date: date})
+		}
+	}
+	return daily, nil
+}
+
+// 來源排行
+func (s *AnalyticsService) topReferrers(postIDs []uint, from, to string) ([]ReferrerStats, error) {
+	var rolled, live []ReferrerStats
+	if err := database.DB.Model(&models.PostReferrerStat{}).
+		Where("post_id IN ? AND date BETWEEN ? AND ?", postIDs, from, to).
+		Select("domain, SUM(views) AS views").Group("domain").Scan(&rolled).Error; err != nil {
+		return nil, err
+	}
+	if err := database.DB.Model(&models.PostViewEvent{}).
+		Where("post_id IN ? AND date BETWEEN ? AND ?", postIDs, from, to).
+		Select("referrer AS domain, COUNT(*) AS views").Group("referrer").Scan(&live).Error; err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]int64)
+	for _, list := range [][]ReferrerStats{rolled, live} {
+		for _, r := range list {
+			totals[r.Domain] += r.Views
+		}
+	}
+	referrers := make([]ReferrerStats, 0, len(totals))
+	for domain, views := range totals {
+		referrers = append(referrers, ReferrerStats{Domain: domain, Views: views})
+	}
+	sort.Slice(referrers, func(i, j int) bool {
+		if referrers[i].Views != referrers[j].Views {
+			return referrers[i].Views > referrers[j].Views
+		}
+		return referrers[i].Domain < referrers[j].Domain
+	})
+	if len(referrers) > topReferrersLimit {
+		referrers = referrers[:topReferrersLimit]
+	}
+	return referrers, nil
+}
+
+func sumDaily(daily []DailyStats) (views, visitors int64) {
+	for _, d := range daily {
+		views += d.Views
+		visitors += d.Visitors
+	}
+	return views, visitors
+}
+
+// GetPostStats 獲取單篇文章的瀏覽統計
+func (s *AnalyticsService) GetPostStats(postID uint, from, to string) (*PostStats, error) {
+	ids := []uint{postID}
+	daily, err := s.dailyStats(ids, from, to)
+	if err != nil {
+		return nil, err
+	}
+	referrers, err := s.topReferrers(ids, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &PostStats{PostID: postID, From: from, To: to, Daily: daily, Referrers: referrers}
+	stats.Views, stats.Visitors = sumDaily(daily)
+	return stats, nil
+}
+
+// GetAuthorStats 獲取作者全部文章的瀏覽統計
+func (s *AnalyticsService) GetAuthorStats(authorID uint, from, to string, topLimit int) (*AuthorStats, error) {
+	var posts []models.Post
+	if err := database.DB.Select("id", "title").Where("author_id = ?", authorID).Find(&posts).Error; err != nil {
+		return nil, err
+	}
+
+	stats := &AuthorStats{AuthorID: authorID, From: from, To: to, TopPosts: []TopPost{}, Referrers: []ReferrerStats{}}
+	ids := make([]uint, 0, len(posts))
+	titles := make(map[uint]string, len(posts))
+	for _, post := range posts {
+		ids = append(ids, post.ID)
+		titles[post.ID] = post.Title
+	}
+
+	daily, err := s.dailyStats(ids, from, to)
+	if err != nil {
+		return nil, err
+	}
+	stats.Daily = daily
+	stats.Views, stats.Visitors = sumDaily(daily)
+	if len(ids) == 0 {
+		return stats, nil
+	}
+
+	if stats.Referrers, err = s.topReferrers(ids, from, to); err != nil {
+		return nil, err
+	}
+
+	// 文章排行
+	var rolled, live []TopPost
+	if err := database.DB.Model(&models.PostDailyStat{}).
+		Where("post_id IN ? AND date BETWEEN ? AND ?", ids, from, to).
+		Select("post_id, SUM(views) AS views").Group("post_id").Scan(&rolled).Error; err != nil {
+		return nil, err
+	}
+	if err := database.DB.Model(&models.PostViewEvent{}).
+		Where("post_id IN ? AND date BETWEEN ? AND ?", ids, from, to).
+		Select("post_id, COUNT(*) AS views").Group("post_id").Scan(&live).Error; err != nil {
+		return nil, err
+	}
+	totals := make(map[uint]int64)
+	for _, list := range [][]TopPost{rolled, live} {
+		for _, p := range list {
+			totals[p.PostID] += p.Views
+		}
+	}
+	for id, views := range totals {
+		stats.TopPosts = append(stats.TopPosts, TopPost{PostID: id, Title: titles[id], Views: views})
+	}
+	sort.Slice(stats.TopPosts, func(i, j int) bool {
+		if stats.TopPosts[i].Views != stats.TopPosts[j].Views {
+			return stats.TopPosts[i].Views > stats.TopPosts[j].Views
+		}
+		return stats.TopPosts[i].PostID < stats.TopPosts[j].PostID
+	})
+	if len(stats.TopPosts) > topLimit {
+		stats.TopPosts = stats.TopPosts[:topLimit]
+	}
+	return stats, nil
+}
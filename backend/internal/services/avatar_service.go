@@ -0,0 +1,147 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+
+	"backend/config"
+	"backend/internal/database"
+	"backend/internal/models"
+	"backend/internal/storage"
+	"backend/pkg/imaging"
+
+	"gorm.io/gorm"
+)
+
+// 生成的頭像尺寸，第一個作為默認頭像
+var AvatarSizes = []int{256, 128, 64}
+
+// 允許作為頭像的圖片類型
+var allowedAvatarTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/webp": true,
+}
+
+type AvatarService struct {
+	userService *UserService
+}
+
+func NewAvatarService() *AvatarService {
+	return &AvatarService{
+		userService: NewUserService(),
+	}
+}
+
+// UploadAvatar 上傳頭像：去除元數據、居中裁剪並生成多個尺寸，替換後清理舊文件
+func (s *AvatarService) UploadAvatar(ctx context.Context, userID uint, r io.Reader) (*models.User, error) {
+	data, err := io.ReadAll(io.LimitReader(r, config.AppConfig.MaxUploadSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > config.AppConfig.MaxUploadSize {
+		return nil, ErrFileTooLarge
+	}
+	if !allowedAvatarTypes[sniffMimeType(data)] {
+		return nil, ErrUnsupportedMediaType
+	}
+
+	img, err := imaging.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	square := imaging.CropSquare(img)
+
+	base, err := newStorageKey("avatars", userID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var uploaded []models.Media
+	cleanup := func() {
+		for _, media := range uploaded {
+			storage.Store.Delete(ctx, media.StorageKey)
+		}
+	}
+
+	avatars := make(models.StringMap, len(AvatarSizes))
+	for _, size := range AvatarSizes {
+		encoded, err := imaging.EncodePNG(imaging.Resize(square, size, size))
+		if err != nil {
+			cleanup()
+			return nil, err
+		}
+
+		key := fmt.Sprintf("%s-%d.png", base, size)
+		if err := storage.Store.Put(ctx, key, bytes.NewReader(encoded), int64(len(encoded)), "image/png"); err != nil {
+			cleanup()
+			return nil, err
+		}
+
+		uploaded = append(uploaded, models.Media{
+			UserID:     userID,
+			Filename:   fmt.Sprintf("avatar-%d.png", size),
+			StorageKey: key,
+			Backend:    storage.Store.Name(),
+			MimeType:   "image/png",
+			Size:       int64(len(encoded)),
+			Purpose:    models.MediaPurposeAvatar,
+		})
+		avatars[strconv.Itoa(size)] = models.MediaURLPrefix + key
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&uploaded).Error; err != nil {
+			return err
+		}
+		if err := bumpVersion(tx, &models.User{}, userID, 0); err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"avatar":  avatars[strconv.Itoa(AvatarSizes[0])],
+			"avatars": avatars,
+		}).Error
+	})
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+	invalidateUsers(userID)
+
+	keep := make([]uint, len(uploaded))
+	for i, media := range uploaded {
+		keep[i] = media.ID
+	}
+	if err := s.removeAvatarFiles(ctx, userID, keep); err != nil {
+		log.Println("清理舊頭像失敗:", err)
+	}
+
+	return s.userService.GetUserByID(userID)
+}
+
+// 刪除用戶除 keep 以外的頭像文件
+func (s *AvatarService) removeAvatarFiles(ctx context.Context, userID uint, keep []uint) error {
+	query := database.DB.Where("user_id = ? AND purpose = ?", userID, models.MediaPurposeAvatar)
+	if len(keep) > 0 {
+		query = query.Where("id NOT IN ?", keep)
+	}
+
+	var old []models.Media
+	if err := query.Find(&old).Error; err != nil {
+		return err
+	}
+
+	for i := range old {
+		if err := storage.Store.Delete(ctx, old[i].StorageKey); err != nil {
+			return err
+		}
+		if err := database.DB.Unscoped().Delete(&old[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"backend/internal/database"
+	"backend/internal/models"
+	"backend/pkg/utils"
+
+	"gorm.io/gorm/clause"
+)
+
+type BookmarkService struct{}
+
+func NewBookmarkService() *BookmarkService {
+	return &BookmarkService{}
+}
+
+// 收藏文章，重複收藏不會報錯
+func (s *BookmarkService) AddBookmark(postID, userID uint) error {
+	if err := database.DB.First(&models.Post{}, postID).Error; err != nil {
+		return err
+	}
+
+	bookmark := models.Bookmark{
+		PostID: postID,
+		UserID: userID,
+	}
+	return database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&bookmark).Error
+}
+
+// 取消收藏
+func (s *BookmarkService) RemoveBookmark(postID, userID uint) error {
+	return database.DB.Where("post_id = ? AND user_id = ?", postID, userID).Delete(&models.Bookmark{}).Error
+}
+
+// 是否已收藏
+func (s *BookmarkService) IsBookmarked(postID, userID uint) (bool, error) {
+	var count int64
+	err := database.DB.Model(&models.Bookmark{}).
+		Where("post_id = ? AND user_id = ?", postID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// 統計文章收藏數，忽略已刪除用戶
+func (s *BookmarkService) CountBookmarks(postID uint) (int64, error) {
+	var count int64
+	err := database.DB.Model(&models.Bookmark{}).
+		Joins("JOIN users ON users.id = bookmarks.user_id AND users.deleted_at IS NULL").
+		Where("bookmarks.post_id = ?", postID).
+		Count(&count).Error
+	return count, err
+}
+
+// 獲取用戶收藏列表，已刪除、未發布或超出可見範圍的文章不會出現
+func (s *BookmarkService) GetBookmarks(actor Actor, page, limit int) ([]models.Bookmark, int64, error) {
+	var bookmarks []models.Bookmark
+	var total int64
+
+	query := database.DB.Model(&models.Bookmark{}).
+		Joins("JOIN posts ON posts.id = bookmarks.post_id AND posts.deleted_at IS NULL").
+		Where("bookmarks.user_id = ?", actor.UserID)
+	query = readablePosts(query, actor)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload("Post.Author").Preload("Post.Tags").
+		Offset(utils.GetOffset(page, limit)).Limit(limit).
+		Order("bookmarks.created_at DESC").
+		Find(&bookmarks).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return bookmarks, total, nil
+}
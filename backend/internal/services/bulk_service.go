@@ -0,0 +1,411 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"backend/internal/database"
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// 單次批量操作最多處理的項目數
+const MaxBulkItems = 500
+
+// 文章批量操作
+const (
+	BulkPostPublish        = "publish"
+	BulkPostArchive        = "archive"
+	BulkPostDelete         = "delete"
+	BulkPostAddTags        = "add_tags"
+	BulkPostRemoveTags     = "remove_tags"
+	BulkPostReassignAuthor = "reassign_author"
+)
+
+// 用戶批量操作
+const (
+	BulkUserActivate   = "activate"
+	BulkUserDisable    = "disable"
+	BulkUserDelete     = "delete"
+	BulkUserChangeRole = "change_role"
+)
+
+// 單個項目的處理結果
+const (
+	BulkStatusOK      = "ok"
+	BulkStatusSkipped = "skipped" // 已是目標狀態，無需變更
+	BulkStatusFailed  = "failed"
+	// 原子模式下有項目失敗時，已執行成功的項目隨整個事務回滾
+	BulkStatusRolledBack = "rolled_back"
+)
+
+var (
+	ErrInvalidBulkAction  = errors.New("不支持的批量操作")
+	ErrBulkTargetRequired = errors.New("請提供 ids 或 filter 其中之一")
+	ErrBulkTooManyItems   = fmt.Errorf("單次批量操作最多 %d 項", MaxBulkItems)
+	ErrBulkTagsRequired   = errors.New("請提供 tag_ids")
+	ErrTagNotFound        = errors.New("標籤不存在")
+	ErrAuthorNotFound     = errors.New("作者不存在")
+	ErrInvalidRole        = errors.New("無效的角色")
+	ErrBulkSelf           = errors.New("不能對自己的帳號執行此操作")
+
+	// 試運行結束時回滾事務
+	errBulkDryRun = errors.New("dry run")
+	// 原子模式下首個項目失敗時中止並回滾事務
+	errBulkAborted = errors.New("bulk aborted")
+)
+
+// 批量操作中單個項目的結果
+type BulkItemResult struct {
+	ID     uint   `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// 批量操作報告
+type BulkReport struct {
+	Action     string           `json:"action"`
+	DryRun     bool             `json:"dry_run"`
+	Atomic     bool             `json:"atomic"`
+	RolledBack bool             `json:"rolled_back"` // 原子模式下因失敗回滾了全部變更
+	Matched    int              `json:"matched"`
+	Succeeded  int              `json:"succeeded"`
+	Skipped    int              `json:"skipped"`
+	Failed     int              `json:"failed"`
+	Results    []BulkItemResult `json:"results"`
+}
+
+func (r *BulkReport) add(id uint, err error, changed bool) {
+	result := BulkItemResult{ID: id, Status: BulkStatusOK}
+	switch {
+	case err != nil:
+		result.Status = BulkStatusFailed
+		result.Error = err.Error()
+		r.Failed++
+	case !changed:
+		result.Status = BulkStatusSkipped
+		r.Skipped++
+	default:
+		r.Succeeded++
+	}
+	r.Results = append(r.Results, result)
+}
+
+// 回滾全部變更：成功的項目標記為已回滾，未處理的項目不出現在結果中
+func (r *BulkReport) rollBack() {
+	r.RolledBack = true
+	r.Succeeded = 0
+	for i := range r.Results {
+		if r.Results[i].Status == BulkStatusOK {
+			r.Results[i].Status = BulkStatusRolledBack
+		}
+	}
+}
+
+// 成功變更的項目ID
+func (r *BulkReport) changedIDs() []uint {
+	var ids []uint
+	for _, result := range r.Results {
+		if result.Status == BulkStatusOK {
+			ids = append(ids, result.ID)
+		}
+	}
+	return ids
+}
+
+// 文章批量操作的目標與參數，IDs 與 Filter 二選一
+type BulkPostRequest struct {
+	Action   string
+	IDs      []uint
+	Filter   *PostFilter
+	DryRun   bool
+	Atomic   bool   // 任一項目失敗時中止並回滾全部變更
+	TagIDs   []uint // add_tags、remove_tags
+	AuthorID uint   // reassign_author
+}
+
+// 用戶批量操作的目標與參數，IDs 與 Filter 二選一
+type BulkUserRequest struct {
+	Action string
+	IDs    []uint
+	Filter *UserFilter
+	DryRun bool
+	Atomic bool   // 任一項目失敗時中止並回滾全部變更
+	Role   string // change_role
+}
+
+type BulkService struct{}
+
+func NewBulkService() *BulkService {
+	return &BulkService{}
+}
+
+// 解析批量操作的目標ID：明確的ID列表保持原順序去重，篩選條件按ID升序
+func resolveBulkIDs(ids []uint, filtered *gorm.DB) ([]uint, error) {
+	if filtered == nil {
+		ids = uniqueIDs(ids)
+		if len(ids) > MaxBulkItems {
+			return nil, ErrBulkTooManyItems
+		}
+		return ids, nil
+	}
+
+	var matched []uint
+	if err := filtered.Order("id ASC").Limit(MaxBulkItems+1).Pluck("id", &matched).Error; err != nil {
+		return nil, err
+	}
+	if len(matched) > MaxBulkItems {
+		return nil, ErrBulkTooManyItems
+	}
+	return matched, nil
+}
+
+// 在單個事務中逐項執行，每項使用保存點，失敗的項目只回滾自身
+// 原子模式下首個失敗的項目會中止執行並回滾整個事務；試運行時執行完畢後回滾整個事務
+func runBulk(report *BulkReport, dryRun bool, fn func(tx *gorm.DB, report *BulkReport) error) error {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := fn(tx, report); err != nil {
+			return err
+		}
+		if dryRun {
+			return errBulkDryRun
+		}
+		return nil
+	})
+	switch {
+	case errors.Is(err, errBulkAborted):
+		report.rollBack()
+		return nil
+	case errors.Is(err, errBulkDryRun):
+		return nil
+	}
+	return err
+}
+
+// 執行單項操作並記錄結果；原子模式下項目失敗時返回 errBulkAborted
+func bulkItem(tx *gorm.DB, report *BulkReport, id uint, fn func(tx *gorm.DB) (bool, error)) error {
+	var changed bool
+	err := tx.Transaction(func(tx *gorm.DB) error {
+		var err error
+		changed, err = fn(tx)
+		return err
+	})
+	report.add(id, err, changed)
+	if err != nil && report.Atomic {
+		return errBulkAborted
+	}
+	return nil
+}
+
+// BulkPosts 批量處理文章
+func (s *BulkService) BulkPosts(req BulkPostRequest, actor Actor) (*BulkReport, error) {
+	if (len(req.IDs) == 0) == (req.Filter == nil || req.Filter.IsEmpty()) {
+		return nil, ErrBulkTargetRequired
+	}
+
+	var tags []models.Tag
+	switch req.Action {
+	case BulkPostPublish, BulkPostArchive, BulkPostDelete:
+	case BulkPostAddTags, BulkPostRemoveTags:
+		tagIDs := uniqueIDs(req.TagIDs)
+		if len(tagIDs) == 0 {
+			return nil, ErrBulkTagsRequired
+		}
+		if err := database.DB.Where("id IN ?", tagIDs).Find(&tags).Error; err != nil {
+			return nil, err
+		}
+		if len(tags) != len(tagIDs) {
+			return nil, ErrTagNotFound
+		}
+	case BulkPostReassignAuthor:
+		var count int64
+		if err := database.DB.Model(&models.User{}).Where("id = ?", req.AuthorID).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return nil, ErrAuthorNotFound
+		}
+	default:
+		return nil, ErrInvalidBulkAction
+	}
+
+	report := &BulkReport{Action: req.Action, DryRun: req.DryRun, Atomic: req.Atomic, Results: []BulkItemResult{}}
+	err := runBulk(report, req.DryRun, func(tx *gorm.DB, report *BulkReport) error {
+		var filtered *gorm.DB
+		if req.Filter != nil && len(req.IDs) == 0 {
+			filtered = applyPostFilter(tx.Model(&models.Post{}), *req.Filter)
+		}
+		ids, err := resolveBulkIDs(req.IDs, filtered)
+		if err != nil {
+			return err
+		}
+		report.Matched = len(ids)
+
+		for _, id := range ids {
+			err := bulkItem(tx, report, id, func(tx *gorm.DB) (bool, error) {
+				var post models.Post
+				if err := tx.First(&post, id).Error; err != nil {
+					if errors.Is(err, gorm.ErrRecordNotFound) {
+						return false, errors.New("文章不存在")
+					}
+					return false, err
+				}
+				changed, err := applyBulkPostAction(tx, &post, req, tags, actor)
+				if err != nil || !changed || req.Action == BulkPostDelete {
+					return changed, err
+				}
+				return true, bumpVersion(tx, &models.Post{}, post.ID, 0)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !req.DryRun && !report.RolledBack {
+		invalidatePosts(report.changedIDs()...)
+	}
+	return report, nil
+}
+
+func applyBulkPostAction(tx *gorm.DB, post *models.Post, req BulkPostRequest, tags []models.Tag, actor Actor) (bool, error) {
+	switch req.Action {
+	case BulkPostPublish, BulkPostArchive:
+		to := models.PostStatusPublished
+		if req.Action == BulkPostArchive {
+			to = models.PostStatusArchived
+		}
+		if post.Status == to {
+			return false, nil
+		}
+		return true, transitionPostStatus(tx, post, to, actor, "批量操作")
+
+	case BulkPostDelete:
+		return true, tx.Delete(post).Error
+
+	case BulkPostAddTags:
+		var existing []uint
+		if err := tx.Table("post_tags").Where("post_id = ?", post.ID).Pluck("tag_id", &existing).Error; err != nil {
+			return false, err
+		}
+		has := make(map[uint]bool, len(existing))
+		for _, id := range existing {
+			has[id] = true
+		}
+		var missing []models.Tag
+		for _, tag := range tags {
+			if !has[tag.ID] {
+				missing = append(missing, tag)
+			}
+		}
+		if len(missing) == 0 {
+			return false, nil
+		}
+		return true, tx.Model(post).Association("Tags").Append(missing)
+
+	case BulkPostRemoveTags:
+		tagIDs := make([]uint, len(tags))
+		for i, tag := range tags {
+			tagIDs[i] = tag.ID
+		}
+		result := tx.Exec("DELETE FROM post_tags WHERE post_id = ? AND tag_id IN ?", post.ID, tagIDs)
+		return result.RowsAffected > 0, result.Error
+
+	case BulkPostReassignAuthor:
+		if post.AuthorID == req.AuthorID {
+			return false, nil
+		}
+		return true, tx.Model(post).Update("author_id", req.AuthorID).Error
+	}
+	return false, ErrInvalidBulkAction
+}
+
+// BulkUsers 批量處理用戶，操作者本人的帳號不能被禁用、刪除或變更角色
+func (s *BulkService) BulkUsers(req BulkUserRequest, actor Actor) (*BulkReport, error) {
+	if (len(req.IDs) == 0) == (req.Filter == nil || req.Filter.IsEmpty()) {
+		return nil, ErrBulkTargetRequired
+	}
+
+	switch req.Action {
+	case BulkUserActivate, BulkUserDisable, BulkUserDelete:
+	case BulkUserChangeRole:
+		if !IsValidRole(req.Role) {
+			return nil, ErrInvalidRole
+		}
+	default:
+		return nil, ErrInvalidBulkAction
+	}
+
+	report := &BulkReport{Action: req.Action, DryRun: req.DryRun, Atomic: req.Atomic, Results: []BulkItemResult{}}
+	err := runBulk(report, req.DryRun, func(tx *gorm.DB, report *BulkReport) error {
+		var filtered *gorm.DB
+		if req.Filter != nil && len(req.IDs) == 0 {
+			filtered = applyUserFilter(tx.Model(&models.User{}), *req.Filter)
+		}
+		ids, err := resolveBulkIDs(req.IDs, filtered)
+		if err != nil {
+			return err
+		}
+		report.Matched = len(ids)
+
+		for _, id := range ids {
+			err := bulkItem(tx, report, id, func(tx *gorm.DB) (bool, error) {
+				var user models.User
+				if err := tx.First(&user, id).Error; err != nil {
+					if errors.Is(err, gorm.ErrRecordNotFound) {
+						return false, errors.New("用戶不存在")
+					}
+					return false, err
+				}
+				changed, err := applyBulkUserAction(tx, &user, req, actor)
+				if err != nil || !changed || req.Action == BulkUserDelete {
+					return changed, err
+				}
+				return true, bumpVersion(tx, &models.User{}, user.ID, 0)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !req.DryRun && !report.RolledBack {
+		invalidateUsers(report.changedIDs()...)
+	}
+	return report, nil
+}
+
+func applyBulkUserAction(tx *gorm.DB, user *models.User, req BulkUserRequest, actor Actor) (bool, error) {
+	if user.ID == actor.UserID && req.Action != BulkUserActivate {
+		return false, ErrBulkSelf
+	}
+
+	switch req.Action {
+	case BulkUserActivate, BulkUserDisable:
+		status := models.UserStatusActive
+		if req.Action == BulkUserDisable {
+			status = models.UserStatusDisabled
+		}
+		if user.Status == status {
+			return false, nil
+		}
+		return true, tx.Model(user).Update("status", status).Error
+
+	case BulkUserDelete:
+		return true, tx.Delete(user).Error
+
+	case BulkUserChangeRole:
+		if user.Role == req.Role {
+			return false, nil
+		}
+		return true, tx.Model(user).Update("role", req.Role).Error
+	}
+	return false, ErrInvalidBulkAction
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"strconv"
+	"sync"
+
+	"backend/internal/cache"
+)
+
+// 緩存鍵前綴
+const (
+	postCachePrefix     = "post:"
+	postSlugCachePrefix = "post-slug:"
+	userCachePrefix     = "user:"
+	settingCachePrefix  = "setting:"
+)
+
+func postCacheKey(id uint) string {
+	return postCachePrefix + strconv.FormatUint(uint64(id), 10)
+}
+
+func userCacheKey(id uint) string {
+	return userCachePrefix + strconv.FormatUint(uint64(id), 10)
+}
+
+// 緩存代數，每次清除文章或用戶緩存時遞增
+// 讀取數據庫前記下代數，寫回緩存時代數已變化說明讀取期間有寫入提交，讀到的可能是舊數據，不寫回
+var cacheGeneration struct {
+	sync.Mutex
+	value uint64
+}
+
+// 讀取數據庫之前調用，返回當前緩存代數
+func cacheLoadStart() uint64 {
+	cacheGeneration.Lock()
+	defer cacheGeneration.Unlock()
+	return cacheGeneration.value
+}
+
+// 讀取數據庫期間沒有清除過緩存時才寫入緩存
+func cacheLoadDone(key string, generation uint64, v interface{}) {
+	cacheGeneration.Lock()
+	defer cacheGeneration.Unlock()
+	if cacheGeneration.value == generation {
+		cache.SetObject(key, v, 0)
+	}
+}
+
+// 遞增緩存代數並執行清除
+func cacheInvalidate(clear func()) {
+	cacheGeneration.Lock()
+	defer cacheGeneration.Unlock()
+	cacheGeneration.value++
+	clear()
+}
+
+// 文章寫入後清除緩存，需在事務提交之後調用
+func invalidatePosts(ids ...uint) {
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = postCacheKey(id)
+	}
+	cacheInvalidate(func() { cache.Store.Delete(keys...) })
+}
+
+// 清除全部文章緩存，用於標籤、分類等被多篇文章共享的關聯數據變更
+func invalidateAllPosts() {
+	cacheInvalidate(func() { cache.Store.DeletePrefix(postCachePrefix) })
+}
+
+// 用戶寫入後清除緩存，文章緩存中包含作者信息，一併清除
+func invalidateUsers(ids ...uint) {
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = userCacheKey(id)
+	}
+	cacheInvalidate(func() {
+		cache.Store.Delete(keys...)
+		cache.Store.DeletePrefix(postCachePrefix)
+	})
+}
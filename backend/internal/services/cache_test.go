@@ -0,0 +1,85 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"backend/internal/cache"
+	"backend/internal/database"
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+func setupCacheTest(t *testing.T) models.Post {
+	t.Helper()
+	setupTestDB(t, &models.User{}, &models.Post{}, &models.Tag{}, &models.Category{}, &models.PostCollaborator{})
+
+	prev := cache.Store
+	cache.Store = cache.NewLRU(100, time.Minute)
+	t.Cleanup(func() { cache.Store = prev })
+
+	user := models.User{Username: "alice", Email: "alice@example.com", Password: "x"}
+	database.DB.Create(&user)
+	post := models.Post{Title: "old", AuthorID: user.ID}
+	database.DB.Create(&post)
+	return post
+}
+
+// 寫入提交並清除緩存後，讀取得到新數據
+func TestGetPostByIDAfterWrite(t *testing.T) {
+	post := setupCacheTest(t)
+	s := NewPostService()
+
+	if got, err := s.GetPostByID(post.ID); err != nil || got.Title != "old" {
+		t.Fatalf("GetPostByID = %v, %v", got, err)
+	}
+	database.DB.Model(&models.Post{}).Where("id = ?", post.ID).Updates(map[string]interface{}{"title": "new", "version": 2})
+	invalidatePosts(post.ID)
+
+	got, err := s.GetPostByID(post.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "new" || got.Version != 2 {
+		t.Errorf("got title %q version %d, want new version 2", got.Title, got.Version)
+	}
+}
+
+// 讀取數據庫之後、寫回緩存之前有寫入提交並清除緩存時，讀到的舊數據不寫回緩存
+func TestGetPostByIDSkipsStaleFill(t *testing.T) {
+	post := setupCacheTest(t)
+	s := NewPostService()
+
+	// 在讀取文章的查詢完成後模擬另一個請求提交更新並清除緩存
+	written := false
+	err := database.DB.Callback().Query().After("gorm:query").Register("test:concurrent_write", func(db *gorm.DB) {
+		if written || db.Statement.Table != "posts" {
+			return
+		}
+		written = true
+		if err := database.DB.Exec("UPDATE posts SET title = ?, version = version + 1 WHERE id = ?", "new", post.ID).Error; err != nil {
+			t.Error(err)
+		}
+		invalidatePosts(post.ID)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.GetPostByID(post.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "old" {
+		t.Fatalf("first read title = %q, want old", got.Title)
+	}
+
+	got, err = s.GetPostByID(post.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "new" || got.Version != 2 {
+		t.Errorf("second read got title %q version %d, want new version 2", got.Title, got.Version)
+	}
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"backend/internal/database"
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvalidCollaboratorRole = errors.New("無效的協作者角色")
+	ErrCollaboratorIsAuthor    = errors.New("文章作者不能被添加為協作者")
+	ErrCollaboratorNotFound    = errors.New("協作者不存在")
+	ErrCollaboratorUserMissing = errors.New("用戶不存在")
+)
+
+// IsValidCollaboratorRole 檢查協作者角色是否合法
+func IsValidCollaboratorRole(role string) bool {
+	switch role {
+	case models.CollaboratorRoleCoAuthor, models.CollaboratorRoleEditor, models.CollaboratorRoleViewer:
+		return true
+	}
+	return false
+}
+
+// PostPermission 用戶對單篇文章的權限
+type PostPermission struct {
+	Read         bool // 查看未發布的文章與狀態記錄
+	Edit         bool // 編輯文章內容
+	ChangeStatus bool // 變更文章狀態
+	Delete       bool // 刪除文章
+	Manage       bool // 管理協作者
+	ViewStats    bool // 查看瀏覽統計
+}
+
+type CollaboratorService struct{}
+
+func NewCollaboratorService() *CollaboratorService {
+	return &CollaboratorService{}
+}
+
+// GetPermission 計算用戶對文章的權限
+// 管理員與作者擁有全部權限；審核者可查看與變更狀態；協作者按角色授權
+func (s *CollaboratorService) GetPermission(post *models.Post, actor Actor) (PostPermission, error) {
+	if actor.IsAdmin() || (actor.UserID != 0 && post.AuthorID == actor.UserID) {
+		return PostPermission{Read: true, Edit: true, ChangeStatus: true, Delete: true, Manage: true, ViewStats: true}, nil
+	}
+
+	var perm PostPermission
+	if actor.IsReviewer() {
+		perm.Read = true
+		perm.ChangeStatus = true
+	}
+	if actor.UserID == 0 {
+		return perm, nil
+	}
+
+	var collaborator models.PostCollaborator
+	err := database.DB.Where("post_id = ? AND user_id = ?", post.ID, actor.UserID).First(&collaborator).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return perm, nil
+	}
+	if err != nil {
+		return perm, err
+	}
+
+	perm.Read = true
+	switch collaborator.Role {
+	case models.CollaboratorRoleCoAuthor:
+		perm.Edit = true
+		perm.ChangeStatus = true
+		perm.ViewStats = true
+	case models.CollaboratorRoleEditor:
+		perm.Edit = true
+	}
+	return perm, nil
+}
+
+// GetCollaborators 獲取文章的協作者
+func (s *CollaboratorService) GetCollaborators(postID uint) ([]models.PostCollaborator, error) {
+	var collaborators []models.PostCollaborator
+	err := database.DB.Preload("User").
+		Where("post_id = ?", postID).
+		Order("created_at ASC, id ASC").
+		Find(&collaborators).Error
+	return collaborators, err
+}
+
+// SetCollaborator 添加協作者或變更其角色
+func (s *CollaboratorService) SetCollaborator(post *models.Post, userID uint, role string) (*models.PostCollaborator, error) {
+	if !IsValidCollaboratorRole(role) {
+		return nil, ErrInvalidCollaboratorRole
+	}
+	if userID == post.AuthorID {
+		return nil, ErrCollaboratorIsAuthor
+	}
+	var count int64
+	if err := database.DB.Model(&models.User{}).Where("id = ?", userID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, ErrCollaboratorUserMissing
+	}
+
+	collaborator := models.PostCollaborator{PostID: post.ID, UserID: userID, Role: role}
+	err := database.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "post_id"}, {Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"role": role, "updated_at": time.Now()}),
+	}).Create(&collaborator).Error
+	if err != nil {
+		return nil, err
+	}
+	invalidatePosts(post.ID)
+
+	if err := database.DB.Preload("User").
+		Where("post_id = ? AND user_id = ?", post.ID, userID).
+		First(&collaborator).Error; err != nil {
+		return nil, err
+	}
+	return &collaborator, nil
+}
+
+// RemoveCollaborator 移除協作者
+func (s *CollaboratorService) RemoveCollaborator(postID, userID uint) error {
+	result := database.DB.Where("post_id = ? AND user_id = ?", postID, userID).Delete(&models.PostCollaborator{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrCollaboratorNotFound
+	}
+	invalidatePosts(postID)
+	return nil
+}
+
+// 預加載文章的共同作者，忽略已刪除的用戶
+func preloadCoAuthors(query *gorm.DB) *gorm.DB {
+	return query.Preload("CoAuthors", func(db *gorm.DB) *gorm.DB {
+		return db.Joins("JOIN users ON users.id = post_collaborators.user_id AND users.deleted_at IS NULL").
+			Where("post_collaborators.role = ?", models.CollaboratorRoleCoAuthor).
+			Order("post_collaborators.created_at ASC, post_collaborators.id ASC")
+	}).Preload("CoAuthors.User")
+}
+
+// 作者或共同作者為指定用戶的文章ID子查詢
+func coAuthoredPostIDs(db *gorm.DB, userID uint) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).Model(&models.PostCollaborator{}).Select("post_id").
+		Where("user_id = ? AND role = ?", userID, models.CollaboratorRoleCoAuthor)
+}
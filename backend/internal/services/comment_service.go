@@ -0,0 +1,263 @@
+package services
+
+import (
+	"errors"
+
+	"backend/internal/database"
+	"backend/internal/models"
+	"backend/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCommentsClosed       = errors.New("該文章已關閉評論")
+	ErrInvalidParentComment = errors.New("回覆的評論不存在")
+	ErrInvalidCommentStatus = errors.New("無效的評論狀態")
+	ErrCommentTooDeep       = errors.New("回覆層級過深")
+)
+
+// 評論樹最大深度，頂層評論深度為 0
+const maxCommentDepth = 10
+
+type CommentService struct {
+	settingService *SettingService
+}
+
+func NewCommentService() *CommentService {
+	return &CommentService{
+		settingService: NewSettingService(),
+	}
+}
+
+// IsValidCommentStatus 檢查評論狀態是否合法
+func IsValidCommentStatus(status string) bool {
+	switch status {
+	case models.CommentStatusPending, models.CommentStatusApproved,
+		models.CommentStatusRejected, models.CommentStatusSpam:
+		return true
+	}
+	return false
+}
+
+// 新評論的初始狀態：關閉審核或管理員發表時直接通過
+func (s *CommentService) initialStatus(actor Actor) string {
+	if actor.IsAdmin() || !s.settingService.GetBool("comment_moderation", true) {
+		return models.CommentStatusApproved
+	}
+	return models.CommentStatusPending
+}
+
+// 創建評論
+func (s *CommentService) CreateComment(postID uint, parentID *uint, content string, actor Actor) (*models.Comment, error) {
+	var post models.Post
+	if err := database.DB.First(&post, postID).Error; err != nil {
+		return nil, err
+	}
+	if post.CommentsClosed {
+		return nil, ErrCommentsClosed
+	}
+
+	if parentID != nil {
+		// 未通過審核的評論對其他讀者不可見，只有其作者可以回覆，否則回覆會成為看不到的孤兒
+		var parent models.Comment
+		if err := database.DB.Where("post_id = ?", postID).
+			Where("status = ? OR user_id = ?", models.CommentStatusApproved, actor.UserID).
+			First(&parent, *parentID).Error; err != nil {
+			return nil, ErrInvalidParentComment
+		}
+		// 超過最大深度的回覆不會出現在評論樹中，直接拒絕
+		depth, err := commentDepth(&parent)
+		if err != nil {
+			return nil, err
+		}
+		if depth >= maxCommentDepth {
+			return nil, ErrCommentTooDeep
+		}
+	}
+
+	comment := models.Comment{
+		PostID:   postID,
+		UserID:   &actor.UserID,
+		ParentID: parentID,
+		Content:  content,
+		Status:   s.initialStatus(actor),
+	}
+	if err := database.DB.Create(&comment).Error; err != nil {
+		return nil, err
+	}
+
+	return s.GetCommentByID(comment.ID)
+}
+
+// 沿父評論向上計算評論深度，超過最大深度後不再繼續查找
+// 已刪除的父評論仍計入，與評論樹中的佔位一致
+func commentDepth(comment *models.Comment) (int, error) {
+	depth := 0
+	parentID := comment.ParentID
+	for parentID != nil && depth <= maxCommentDepth {
+		var parent models.Comment
+		if err := database.DB.Unscoped().Select("id", "parent_id").First(&parent, *parentID).Error; err != nil {
+			return 0, err
+		}
+		depth++
+		parentID = parent.ParentID
+	}
+	return depth, nil
+}
+
+// 根據 ID 獲取評論
+func (s *CommentService) GetCommentByID(id uint) (*models.Comment, error) {
+	var comment models.Comment
+	if err := database.DB.Preload("User").First(&comment, id).Error; err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// 編輯評論，需要審核時重新進入審核隊列
+func (s *CommentService) UpdateComment(id uint, content string, actor Actor) (*models.Comment, error) {
+	var comment models.Comment
+	if err := database.DB.First(&comment, id).Error; err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{
+		"content": content,
+		"status":  s.initialStatus(actor),
+	}
+	if err := database.DB.Model(&comment).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+
+	return s.GetCommentByID(id)
+}
+
+// 刪除評論（軟刪除，保留回覆）
+func (s *CommentService) DeleteComment(id uint) error {
+	return database.DB.Delete(&models.Comment{}, id).Error
+}
+
+// 獲取文章評論樹，分頁作用於頂層評論
+func (s *CommentService) GetCommentTree(postID uint, page, limit int, actor Actor) ([]*models.Comment, int64, error) {
+	// 已通過的評論，以及自己發表的評論
+	visible := func(db *gorm.DB) *gorm.DB {
+		return db.Where("comments.status = ? OR comments.user_id = ?", models.CommentStatusApproved, actor.UserID)
+	}
+	// 已刪除但仍有回覆的評論保留為佔位
+	alive := func(db *gorm.DB) *gorm.DB {
+		return db.Where("comments.deleted_at IS NULL OR EXISTS (SELECT 1 FROM comments AS r WHERE r.parent_id = comments.id AND r.deleted_at IS NULL)")
+	}
+
+	var total int64
+	rootQuery := database.DB.Unscoped().Model(&models.Comment{}).
+		Where("comments.post_id = ? AND comments.parent_id IS NULL", postID).
+		Scopes(visible, alive)
+	if err := rootQuery.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var roots []*models.Comment
+	if err := rootQuery.Preload("User").
+		Order("comments.created_at ASC").
+		Offset(utils.GetOffset(page, limit)).Limit(limit).
+		Find(&roots).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 逐層載入回覆
+	level := roots
+	for depth := 0; depth < maxCommentDepth && len(level) > 0; depth++ {
+		parents := make(map[uint]*models.Comment, len(level))
+		ids := make([]uint, 0, len(level))
+		for _, comment := range level {
+			parents[comment.ID] = comment
+			ids = append(ids, comment.ID)
+		}
+
+		var replies []*models.Comment
+		if err := database.DB.Unscoped().Preload("User").
+			Where("comments.parent_id IN ?", ids).
+			Scopes(visible, alive).
+			Order("comments.created_at ASC").
+			Find(&replies).Error; err != nil {
+			return nil, 0, err
+		}
+
+		for _, reply := range replies {
+			parent := parents[*reply.ParentID]
+			parent.Replies = append(parent.Replies, reply)
+		}
+		level = replies
+	}
+
+	for _, root := range roots {
+		maskDeletedComments(root)
+	}
+
+	return roots, total, nil
+}
+
+// 隱藏已刪除評論的內容
+func maskDeletedComments(comment *models.Comment) {
+	if comment.DeletedAt.Valid {
+		comment.Deleted = true
+		comment.Content = ""
+		comment.User = nil
+	}
+	for _, reply := range comment.Replies {
+		maskDeletedComments(reply)
+	}
+}
+
+// 獲取審核隊列
+func (s *CommentService) GetModerationQueue(page, limit int, status string) ([]models.Comment, int64, error) {
+	var comments []models.Comment
+	var total int64
+
+	query := database.DB.Model(&models.Comment{}).Where("status = ?", status)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload("User").Preload("Post").
+		Offset(utils.GetOffset(page, limit)).Limit(limit).
+		Order("created_at ASC").
+		Find(&comments).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return comments, total, nil
+}
+
+// 設置評論審核狀態
+func (s *CommentService) SetCommentStatus(id uint, status string) (*models.Comment, error) {
+	if !IsValidCommentStatus(status) {
+		return nil, ErrInvalidCommentStatus
+	}
+
+	var comment models.Comment
+	if err := database.DB.First(&comment, id).Error; err != nil {
+		return nil, err
+	}
+	if err := database.DB.Model(&comment).Update("status", status).Error; err != nil {
+		return nil, err
+	}
+
+	return s.GetCommentByID(id)
+}
+
+// 開啟或關閉文章評論
+func (s *CommentService) SetCommentsClosed(postID uint, closed bool) error {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersion(tx, &models.Post{}, postID, 0); err != nil {
+			return err
+		}
+		return tx.Model(&models.Post{}).Where("id = ?", postID).Update("comments_closed", closed).Error
+	})
+	if err != nil {
+		return err
+	}
+	invalidatePosts(postID)
+	return nil
+}
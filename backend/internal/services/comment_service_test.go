@@ -0,0 +1,52 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"backend/internal/database"
+	"backend/internal/models"
+)
+
+// 只能回覆已通過審核的評論或自己的評論
+func TestCreateCommentParentStatus(t *testing.T) {
+	setupTestDB(t, &models.User{}, &models.Post{}, &models.Comment{}, &models.Setting{})
+
+	alice := models.User{Username: "alice", Email: "alice@example.com", Password: "x"}
+	bob := models.User{Username: "bob", Email: "bob@example.com", Password: "x"}
+	database.DB.Create(&alice)
+	database.DB.Create(&bob)
+	post := models.Post{Title: "post", Status: models.PostStatusPublished, AuthorID: alice.ID}
+	database.DB.Create(&post)
+
+	newComment := func(status string) models.Comment {
+		comment := models.Comment{PostID: post.ID, UserID: &alice.ID, Content: status, Status: status}
+		database.DB.Create(&comment)
+		return comment
+	}
+	approved := newComment(models.CommentStatusApproved)
+	pending := newComment(models.CommentStatusPending)
+	spam := newComment(models.CommentStatusSpam)
+
+	s := NewCommentService()
+	aliceActor := Actor{UserID: alice.ID, Role: RoleUser}
+	bobActor := Actor{UserID: bob.ID, Role: RoleUser}
+
+	tests := []struct {
+		name    string
+		parent  models.Comment
+		actor   Actor
+		wantErr error
+	}{
+		{"approved parent", approved, bobActor, nil},
+		{"pending parent", pending, bobActor, ErrInvalidParentComment},
+		{"spam parent", spam, bobActor, ErrInvalidParentComment},
+		{"own pending parent", pending, aliceActor, nil},
+	}
+	for _, tt := range tests {
+		_, err := s.CreateComment(post.ID, &tt.parent.ID, "reply", tt.actor)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
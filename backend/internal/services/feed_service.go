@@ -0,0 +1,131 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"backend/config"
+	"backend/internal/database"
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// 訂閱源最多輸出的條目數
+const MaxFeedItems = 100
+
+// 訂閱源篩選條件
+type FeedFilter struct {
+	AuthorID   uint
+	TagID      uint
+	CategoryID uint
+}
+
+type FeedService struct {
+	settingService *SettingService
+}
+
+func NewFeedService() *FeedService {
+	return &FeedService{
+		settingService: NewSettingService(),
+	}
+}
+
+// PostURL 文章的對外鏈接
+func PostURL(post *models.Post) string {
+	return config.AppConfig.SiteURL + "/posts/" + url.PathEscape(post.Slug)
+}
+
+// PostFeedID 訂閱源條目的永久標識，使用 tag URI（RFC 4151），修改標題導致 slug 變化時保持不變
+func PostFeedID(post *models.Post) string {
+	host := "localhost"
+	if u, err := url.Parse(config.AppConfig.SiteURL); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+	return fmt.Sprintf("tag:%s,%s:post-%d", host, post.CreatedAt.UTC().Format("2006-01-02"), post.ID)
+}
+
+// 文章的發布時間，早期數據沒有 published_at 時使用創建時間
+func PostPublishedTime(post *models.Post) time.Time {
+	if post.PublishedAt != nil {
+		return *post.PublishedAt
+	}
+	return post.CreatedAt
+}
+
+// 默認條目數：feed_item_count 設置，未設置時與 posts_per_page 一致
+func (s *FeedService) DefaultLimit() int {
+	limit := s.settingService.GetInt("feed_item_count", s.settingService.GetInt("posts_per_page", 10))
+	if limit < 1 || limit > MaxFeedItems {
+		return MaxFeedItems
+	}
+	return limit
+}
+
+// 獲取訂閱源中已發布的公開文章，按發布時間倒序
+func (s *FeedService) GetFeedPosts(filter FeedFilter, limit int) ([]models.Post, error) {
+	var posts []models.Post
+
+	query := visiblePublishedPosts(database.DB.Model(&models.Post{}).Preload("Author").Preload("Tags"), Actor{})
+	err := applyFeedFilter(query, filter).
+		Order("COALESCE(posts.published_at, posts.created_at) DESC").Order("posts.id DESC").
+		Limit(limit).Find(&posts).Error
+	return posts, err
+}
+
+// LastModified 訂閱源的最後修改時間：符合篩選條件的文章（包括未發布與已刪除的）最近的更新或刪除時間
+// 撤回發布、修改可見範圍等操作都會更新文章的 updated_at，文章從訂閱源中移除時也會推進修改時間
+func (s *FeedService) LastModified(filter FeedFilter) (time.Time, error) {
+	var latest time.Time
+	for _, column := range []string{"posts.updated_at", "posts.deleted_at"} {
+		var times []time.Time
+		if err := applyFeedFilter(database.DB.Unscoped().Model(&models.Post{}), filter).
+			Where(column+" IS NOT NULL").
+			Order(column+" DESC").Limit(1).
+			Pluck(column, &times).Error; err != nil {
+			return time.Time{}, err
+		}
+		if len(times) > 0 && times[0].After(latest) {
+			latest = times[0]
+		}
+	}
+	return latest, nil
+}
+
+func applyFeedFilter(query *gorm.DB, filter FeedFilter) *gorm.DB {
+	if filter.AuthorID > 0 {
+		query = query.Where("posts.author_id = ?", filter.AuthorID)
+	}
+	if filter.TagID > 0 {
+		query = query.Where("posts.id IN (?)", query.Session(&gorm.Session{NewDB: true}).
+			Table("post_tags").Select("post_id").Where("tag_id = ?", filter.TagID))
+	}
+	if filter.CategoryID > 0 {
+		query = query.Where("posts.category_id = ?", filter.CategoryID)
+	}
+	return query
+}
+
+// 站點名稱與描述
+func (s *FeedService) SiteInfo() (name, description string) {
+	return s.settingService.Get("site_name", "Gin Admin"), s.settingService.Get("site_description", "")
+}
+
+// 根據用戶名獲取作者
+func (s *FeedService) GetAuthorByUsername(username string) (*models.User, error) {
+	var user models.User
+	if err := database.DB.Where("username = ?", username).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// 根據名稱獲取標籤
+func (s *FeedService) GetTagByName(name string) (*models.Tag, error) {
+	var tag models.Tag
+	if err := database.DB.Where("name = ?", name).First(&tag).Error; err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
@@ -0,0 +1,617 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/config"
+	"backend/internal/database"
+	"backend/internal/models"
+	"backend/pkg/markdown"
+	"backend/pkg/utils"
+	"backend/pkg/wxr"
+
+	"gorm.io/gorm"
+)
+
+// 導入來源
+const ImportSourceWordPress = "wordpress"
+
+const (
+	// 報告中保留的錯誤條數上限
+	maxImportErrors = 100
+	// 進度寫入數據庫的間隔
+	importProgressInterval = time.Second
+	// 導入評論的長度上限
+	maxCommentLength = 10000
+)
+
+var (
+	ErrImportTooLarge  = errors.New("導入文件過大")
+	ErrImportEmpty     = errors.New("導入文件為空")
+	ErrImportNotFound  = errors.New("導入任務不存在")
+	errImportCancelled = errors.New("導入已中斷")
+)
+
+var invalidUsernameChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// 全局導入任務執行器
+var Imports *ImportRunner
+
+// ImportRunner 在後台逐個處理導入任務
+// 上傳的文件先保存到磁盤再流式解析；服務停止時未完成的任務回到等待狀態，重啟後重新導入
+// 導入按外部ID匹配已有數據，重複執行不會產生重複內容
+type ImportRunner struct {
+	postService *PostService
+	notify      chan struct{}
+}
+
+// 初始化全局導入任務執行器
+func InitImportRunner() {
+	Imports = NewImportRunner()
+}
+
+func NewImportRunner() *ImportRunner {
+	return &ImportRunner{
+		postService: NewPostService(),
+		notify:      make(chan struct{}, 1),
+	}
+}
+
+// StartWordPressImport 保存上傳的 WXR 文件並創建導入任務
+func (r *ImportRunner) StartWordPressImport(userID uint, filename string, src io.Reader) (*models.ImportJob, error) {
+	dir := config.AppConfig.ImportPath
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.CreateTemp(dir, "wxr-*.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	limit := config.AppConfig.MaxImportSize
+	n, err := io.Copy(f, io.LimitReader(src, limit+1))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	switch {
+	case err == nil && n > limit:
+		err = ErrImportTooLarge
+	case err == nil && n == 0:
+		err = ErrImportEmpty
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return nil, err
+	}
+
+	job := models.ImportJob{
+		UserID:     userID,
+		Source:     ImportSourceWordPress,
+		Filename:   filename,
+		FilePath:   f.Name(),
+		Status:     models.ImportJobPending,
+		BytesTotal: n,
+		Report:     models.ImportStats{Errors: []models.ImportError{}},
+	}
+	if err := database.DB.Create(&job).Error; err != nil {
+		os.Remove(f.Name())
+		return nil, err
+	}
+
+	select {
+	case r.notify <- struct{}{}:
+	default:
+	}
+	return r.GetJob(job.ID)
+}
+
+// GetJob 獲取導入任務
+func (r *ImportRunner) GetJob(id uint) (*models.ImportJob, error) {
+	var job models.ImportJob
+	if err := database.DB.First(&job, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrImportNotFound
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// GetJobs 分頁獲取導入任務，最新的在前
+func (r *ImportRunner) GetJobs(page, limit int) ([]models.ImportJob, int64, error) {
+	var jobs []models.ImportJob
+	var total int64
+
+	query := database.DB.Model(&models.ImportJob{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * limit
+	if err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&jobs).Error; err != nil {
+		return nil, 0, err
+	}
+	return jobs, total, nil
+}
+
+// Run 處理等待中的導入任務，直到 ctx 取消
+func (r *ImportRunner) Run(ctx context.Context) error {
+	// 上次停止時正在執行的任務重新排隊
+	if err := database.DB.Model(&models.ImportJob{}).
+		Where("status = ?", models.ImportJobRunning).
+		Update("status", models.ImportJobPending).Error; err != nil {
+		return err
+	}
+
+	for {
+		var job models.ImportJob
+		err := database.DB.Where("status = ?", models.ImportJobPending).Order("id ASC").First(&job).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-r.notify:
+				continue
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		r.process(ctx, &job)
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// 執行單個任務並記錄最終狀態
+func (r *ImportRunner) process(ctx context.Context, job *models.ImportJob) {
+	now := time.Now()
+	job.Status = models.ImportJobRunning
+	job.StartedAt = &now
+	job.BytesRead = 0
+	job.Report = models.ImportStats{Errors: []models.ImportError{}}
+	job.Error = ""
+	if err := database.DB.Save(job).Error; err != nil {
+		log.Printf("導入任務 %d 啟動失敗: %v", job.ID, err)
+		return
+	}
+
+	err := r.runWordPress(ctx, job)
+	if errors.Is(err, errImportCancelled) {
+		// 保留文件，重啟後重新導入
+		database.DB.Model(job).Updates(map[string]interface{}{
+			"status":     models.ImportJobPending,
+			"bytes_read": job.BytesRead,
+			"report":     job.Report,
+		})
+		return
+	}
+
+	finished := time.Now()
+	job.FinishedAt = &finished
+	job.Status = models.ImportJobCompleted
+	if err != nil {
+		job.Status = models.ImportJobFailed
+		job.Error = err.Error()
+	} else {
+		job.BytesRead = job.BytesTotal
+	}
+	if err := database.DB.Save(job).Error; err != nil {
+		log.Printf("保存導入任務 %d 失敗: %v", job.ID, err)
+	}
+	os.Remove(job.FilePath)
+}
+
+// WordPress 導入過程中的映射關係
+type wxrImport struct {
+	job   *models.ImportJob
+	actor Actor
+
+	usernames  map[string]string // WordPress 登錄名 → 用戶名
+	userIDs    map[int]uint      // WordPress 用戶ID → 用戶ID
+	categories map[string]uint   // 分類別名 → 分類ID
+	parents    map[string]string // 分類別名 → 父分類別名
+}
+
+func (w *wxrImport) fail(item string, err error) {
+	w.job.Report.Failed++
+	w.addError(item, err)
+}
+
+func (w *wxrImport) addError(item string, err error) {
+	if len(w.job.Report.Errors) < maxImportErrors {
+		w.job.Report.Errors = append(w.job.Report.Errors, models.ImportError{Item: item, Error: err.Error()})
+	}
+}
+
+// 流式解析 WXR 文件並逐條導入
+func (r *ImportRunner) runWordPress(ctx context.Context, job *models.ImportJob) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("導入異常: %v", p)
+		}
+	}()
+
+	var user models.User
+	if err := database.DB.First(&user, job.UserID).Error; err != nil {
+		return errors.New("執行導入的用戶不存在")
+	}
+
+	f, err := os.Open(job.FilePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := &wxrImport{
+		job:        job,
+		actor:      Actor{UserID: user.ID, Role: user.Role},
+		usernames:  make(map[string]string),
+		userIDs:    make(map[int]uint),
+		categories: make(map[string]uint),
+		parents:    make(map[string]string),
+	}
+
+	dec := wxr.NewDecoder(bufio.NewReader(f))
+	lastSave := time.Now()
+	for {
+		if ctx.Err() != nil {
+			return errImportCancelled
+		}
+
+		entry, err := dec.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("解析 WXR 文件失敗: %w", err)
+		}
+
+		switch v := entry.(type) {
+		case *wxr.Author:
+			w.importAuthor(v)
+		case *wxr.Category:
+			w.importCategory(v)
+		case *wxr.Tag:
+			w.importTag(v)
+		case *wxr.Item:
+			r.importItem(w, v)
+		}
+
+		if time.Since(lastSave) >= importProgressInterval {
+			job.BytesRead = dec.InputOffset()
+			database.DB.Model(job).Updates(map[string]interface{}{
+				"bytes_read": job.BytesRead,
+				"report":     job.Report,
+			})
+			lastSave = time.Now()
+		}
+	}
+
+	return w.linkCategoryParents()
+}
+
+// 導入作者，按郵箱或用戶名匹配已有用戶，否則創建不可登錄的用戶（需重置密碼）
+func (w *wxrImport) importAuthor(a *wxr.Author) {
+	login := strings.TrimSpace(a.Login)
+	if login == "" {
+		return
+	}
+	label := "作者 " + login
+
+	var user models.User
+	err := gorm.ErrRecordNotFound
+	if email := strings.TrimSpace(a.Email); email != "" {
+		err = database.DB.Where("email = ?", email).First(&user).Error
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = database.DB.Where("username = ?", login).First(&user).Error
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		user, err = createImportedUser(login, strings.TrimSpace(a.Email))
+	}
+	if err != nil {
+		w.addError(label, err)
+		return
+	}
+
+	w.job.Report.Authors++
+	w.usernames[login] = user.Username
+	if a.ID != 0 {
+		w.userIDs[a.ID] = user.ID
+	}
+}
+
+// 創建導入的用戶，使用隨機密碼
+func createImportedUser(login, email string) (models.User, error) {
+	username := invalidUsernameChars.ReplaceAllString(login, "_")
+	if len(username) > 50 {
+		username = username[:50]
+	}
+	if email == "" {
+		email = username + "@wordpress.invalid"
+	}
+
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return models.User{}, err
+	}
+	password, err := utils.HashPassword(hex.EncodeToString(buf))
+	if err != nil {
+		return models.User{}, err
+	}
+
+	var count int64
+	if err := database.DB.Model(&models.User{}).Where("username = ?", username).Count(&count).Error; err != nil {
+		return models.User{}, err
+	}
+	if count > 0 {
+		return models.User{}, ErrUsernameTaken
+	}
+
+	user := models.User{
+		Username: username,
+		Email:    email,
+		Password: password,
+		Role:     RoleUser,
+		Status:   models.UserStatusActive,
+	}
+	return user, database.DB.Create(&user).Error
+}
+
+// 導入分類，父分類在全部分類讀取後關聯
+func (w *wxrImport) importCategory(c *wxr.Category) {
+	name := strings.TrimSpace(c.Name)
+	if name == "" {
+		return
+	}
+	category, err := findOrCreateCategory(database.DB, name)
+	if err != nil {
+		w.addError("分類 "+name, err)
+		return
+	}
+	w.job.Report.Categories++
+	w.categories[c.Nicename] = category.ID
+	if c.Parent != "" {
+		w.parents[c.Nicename] = c.Parent
+	}
+}
+
+// 關聯父分類，只設置尚未設置父分類的分類
+func (w *wxrImport) linkCategoryParents() error {
+	linked := false
+	for nicename, parent := range w.parents {
+		id, ok := w.categories[nicename]
+		parentID, parentOK := w.categories[parent]
+		if !ok || !parentOK || id == parentID {
+			continue
+		}
+		result := database.DB.Model(&models.Category{}).
+			Where("id = ? AND parent_id IS NULL", id).
+			Update("parent_id", parentID)
+		if result.Error != nil {
+			return result.Error
+		}
+		linked = linked || result.RowsAffected > 0
+	}
+	// 文章緩存中包含分類信息
+	if linked {
+		invalidateAllPosts()
+	}
+	return nil
+}
+
+func (w *wxrImport) importTag(t *wxr.Tag) {
+	name := strings.TrimSpace(t.Name)
+	if name == "" {
+		return
+	}
+	if _, err := findOrCreateTag(database.DB, name); err != nil {
+		w.addError("標籤 "+name, err)
+		return
+	}
+	w.job.Report.Tags++
+}
+
+// WordPress 文章狀態對應的文章狀態，不在表中的狀態（回收站、自動草稿等）跳過
+var wordPressStatuses = map[string]string{
+	"publish": models.PostStatusPublished,
+	"pending": models.PostStatusInReview,
+	"draft":   models.PostStatusDraft,
+	"future":  models.PostStatusDraft,
+	"private": models.PostStatusPublished, // 私密文章導入為 restricted，只有作者與審核者可見
+}
+
+// 導入文章及其評論，頁面、附件等其他類型跳過
+func (r *ImportRunner) importItem(w *wxrImport, item *wxr.Item) {
+	status, ok := wordPressStatuses[item.Status]
+	if item.Type != "post" || !ok {
+		w.job.Report.Skipped++
+		return
+	}
+
+	title := strings.TrimSpace(item.Title)
+	if title == "" {
+		title = fmt.Sprintf("未命名文章 #%d", item.ID)
+	}
+	label := fmt.Sprintf("文章 #%d %s", item.ID, title)
+
+	doc := &PostDocument{
+		Title:      title,
+		ExternalID: wordPressExternalID(item),
+		Status:     status,
+		Author:     w.usernames[item.Creator],
+		Summary:    htmlToText(item.Excerpt(), 497),
+		Content:    markdown.FromHTML(item.Content()),
+		CreatedAt:  wxr.ParseTime(item.DateGMT),
+		UpdatedAt:  wxr.ParseTime(item.ModifiedGMT),
+	}
+	if item.Status == "private" {
+		doc.Visibility = models.PostVisibilityRestricted
+	}
+	if name, err := url.PathUnescape(item.Name); err == nil {
+		doc.Slug = utils.Slugify(name)
+	}
+	if status == models.PostStatusPublished {
+		doc.PublishedAt = doc.CreatedAt
+	}
+	for _, term := range item.Terms {
+		name := strings.TrimSpace(term.Name)
+		switch {
+		case name == "":
+		case term.Domain == "category" && doc.Category == "":
+			doc.Category = name
+		case term.Domain == "post_tag":
+			doc.Tags = append(doc.Tags, name)
+		}
+	}
+
+	post, created, _, err := r.postService.ImportPost(doc, w.actor)
+	if err != nil {
+		w.fail(label, err)
+		return
+	}
+	if created {
+		w.job.Report.Created++
+	} else {
+		w.job.Report.Updated++
+	}
+
+	if len(item.Comments) > 0 {
+		count, err := w.importComments(post.ID, item.Comments)
+		w.job.Report.Comments += count
+		if err != nil {
+			w.addError(label+" 的評論", err)
+		}
+	}
+}
+
+// 文章的外部ID，優先使用 GUID，過長時使用摘要
+func wordPressExternalID(item *wxr.Item) string {
+	guid := strings.TrimSpace(item.GUID)
+	if guid == "" {
+		guid = "post-" + strconv.Itoa(item.ID)
+	}
+	id := "wp:" + guid
+	if len(id) > 200 {
+		sum := sha1.Sum([]byte(guid))
+		id = "wp:" + hex.EncodeToString(sum[:])
+	}
+	return id
+}
+
+// 評論者對應的用戶ID，訪客評論或未導入的用戶返回 nil
+func (w *wxrImport) commentUserID(wpUserID int) *uint {
+	if id, ok := w.userIDs[wpUserID]; ok && wpUserID != 0 {
+		return &id
+	}
+	return nil
+}
+
+// 導入評論，按外部ID更新已有評論，返回導入的評論數
+// 引用通告與回收站中的評論跳過，回覆關係在全部評論寫入後建立
+func (w *wxrImport) importComments(postID uint, comments []wxr.Comment) (int, error) {
+	count := 0
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		ids := make(map[int]uint)
+		var replies []wxr.Comment
+
+		for _, c := range comments {
+			if c.Type == "pingback" || c.Type == "trackback" || c.Approved == "trash" {
+				continue
+			}
+			content := htmlToText(c.Content, maxCommentLength)
+			if content == "" {
+				continue
+			}
+
+			status := models.CommentStatusPending
+			switch c.Approved {
+			case "1":
+				status = models.CommentStatusApproved
+			case "spam":
+				status = models.CommentStatusSpam
+			}
+
+			comment := models.Comment{
+				PostID:     postID,
+				UserID:     w.commentUserID(c.UserID),
+				Content:    content,
+				Status:     status,
+				AuthorName: markdown.Truncate(strings.TrimSpace(c.Author), 97),
+				ExternalID: fmt.Sprintf("wp:%d", c.ID),
+			}
+			if t := wxr.ParseTime(c.DateGMT); t != nil {
+				comment.CreatedAt = *t
+				comment.UpdatedAt = *t
+			}
+
+			var existing models.Comment
+			err := tx.Unscoped().Where("post_id = ? AND external_id = ?", postID, comment.ExternalID).First(&existing).Error
+			switch {
+			case err == nil:
+				comment.ID = existing.ID
+				if err := tx.Unscoped().Model(&existing).UpdateColumns(map[string]interface{}{
+					"user_id":     comment.UserID,
+					"content":     comment.Content,
+					"status":      comment.Status,
+					"author_name": comment.AuthorName,
+				}).Error; err != nil {
+					return err
+				}
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				if err := tx.Create(&comment).Error; err != nil {
+					return err
+				}
+			default:
+				return err
+			}
+
+			ids[c.ID] = comment.ID
+			if c.Parent != 0 {
+				replies = append(replies, c)
+			}
+			count++
+		}
+
+		for _, c := range replies {
+			parentID, ok := ids[c.Parent]
+			if !ok {
+				continue
+			}
+			if err := tx.Unscoped().Model(&models.Comment{}).
+				Where("id = ?", ids[c.ID]).
+				UpdateColumn("parent_id", parentID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// HTML 轉換為純文本，超出長度時截斷
+func htmlToText(src string, maxRunes int) string {
+	if strings.TrimSpace(src) == "" {
+		return ""
+	}
+	return strings.TrimSpace(markdown.Summary(markdown.FromHTML(src), maxRunes))
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"backend/config"
+	"backend/internal/database"
+	"backend/internal/models"
+	"backend/internal/storage"
+	"backend/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnsupportedMediaType = errors.New("不支持的文件類型")
+	ErrFileTooLarge         = errors.New("文件超過大小限制")
+	ErrQuotaExceeded        = errors.New("存儲空間不足")
+	ErrMediaInUse           = errors.New("文件仍被文章引用")
+)
+
+// 允許上傳的文件類型及對應擴展名，以內容嗅探結果為準
+var allowedMediaTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+	"text/plain":      ".txt",
+	"video/mp4":       ".mp4",
+	"audio/mpeg":      ".mp3",
+	"application/zip": ".zip",
+}
+
+type MediaService struct{}
+
+func NewMediaService() *MediaService {
+	return &MediaService{}
+}
+
+// 嗅探文件類型，返回去除參數的 MIME 類型
+func sniffMimeType(head []byte) string {
+	mimeType := http.DetectContentType(head)
+	if i := strings.Index(mimeType, ";"); i >= 0 {
+		mimeType = mimeType[:i]
+	}
+	return strings.TrimSpace(mimeType)
+}
+
+// 生成存儲路徑：media/{用戶}/{年}/{月}/{隨機串}{擴展名}
+func newStorageKey(prefix string, userID uint, ext string) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s/%d/%s/%s%s", prefix, userID, time.Now().Format("2006/01"), hex.EncodeToString(buf), ext), nil
+}
+
+// Upload 上傳文件，校驗類型、大小與用戶配額
+func (s *MediaService) Upload(ctx context.Context, userID uint, filename string, r io.Reader, size int64) (*models.Media, error) {
+	if size > config.AppConfig.MaxUploadSize {
+		return nil, ErrFileTooLarge
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	head = head[:n]
+
+	mimeType := sniffMimeType(head)
+	ext, ok := allowedMediaTypes[mimeType]
+	if !ok {
+		return nil, ErrUnsupportedMediaType
+	}
+
+	key, err := newStorageKey("media", userID, ext)
+	if err != nil {
+		return nil, err
+	}
+
+	// 先在同一事務中校驗配額並寫入記錄預留空間，並發上傳不會超出配額
+	media := models.Media{
+		UserID:     userID,
+		Filename:   filename,
+		StorageKey: key,
+		Backend:    storage.Store.Name(),
+		MimeType:   mimeType,
+		Size:       size,
+		Purpose:    models.MediaPurposeLibrary,
+	}
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		used, err := mediaUsage(tx, userID)
+		if err != nil {
+			return err
+		}
+		if used+size > config.AppConfig.UserStorageQuota {
+			return ErrQuotaExceeded
+		}
+		return tx.Create(&media).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	body := io.MultiReader(bytes.NewReader(head), r)
+	if err := storage.Store.Put(ctx, key, body, size, mimeType); err != nil {
+		// 上傳失敗時釋放預留的空間
+		database.DB.Unscoped().Delete(&models.Media{}, media.ID)
+		return nil, err
+	}
+
+	return s.GetMediaByID(media.ID)
+}
+
+// 獲取用戶媒體庫已用空間（字節），頭像不計入
+func (s *MediaService) GetUsage(userID uint) (int64, error) {
+	return mediaUsage(database.DB, userID)
+}
+
+func mediaUsage(db *gorm.DB, userID uint) (int64, error) {
+	var used int64
+	err := db.Model(&models.Media{}).
+		Where("user_id = ? AND purpose = ?", userID, models.MediaPurposeLibrary).
+		Select("COALESCE(SUM(size), 0)").
+		Scan(&used).Error
+	return used, err
+}
+
+// 獲取媒體列表，userID 為 0 時返回全部
+func (s *MediaService) GetMediaList(page, limit int, userID uint) ([]models.Media, int64, error) {
+	var media []models.Media
+	var total int64
+
+	query := database.DB.Model(&models.Media{}).Where("purpose = ?", models.MediaPurposeLibrary)
+	if userID > 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Offset(utils.GetOffset(page, limit)).Limit(limit).Order("created_at DESC").Find(&media).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return media, total, nil
+}
+
+// 根據 ID 獲取媒體
+func (s *MediaService) GetMediaByID(id uint) (*models.Media, error) {
+	var media models.Media
+	if err := database.DB.First(&media, id).Error; err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+// 根據存儲路徑獲取媒體
+func (s *MediaService) GetMediaByKey(key string) (*models.Media, error) {
+	var media models.Media
+	if err := database.DB.Where("storage_key = ?", key).First(&media).Error; err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+// 查找引用該文件的文章（含回收站中的文章）
+func (s *MediaService) FindReferences(media *models.Media) ([]uint, error) {
+	return findMediaReferences(database.DB, media.StorageKey)
+}
+
+func findMediaReferences(db *gorm.DB, key string) ([]uint, error) {
+	var postIDs []uint
+	err := db.Unscoped().Model(&models.Post{}).
+		Where("content LIKE ?", "%"+key+"%").
+		Order("id").Pluck("id", &postIDs).Error
+	return postIDs, err
+}
+
+// DeleteMedia 刪除媒體文件，仍被文章引用時拒絕刪除
+func (s *MediaService) DeleteMedia(ctx context.Context, media *models.Media) ([]uint, error) {
+	postIDs, err := s.FindReferences(media)
+	if err != nil {
+		return nil, err
+	}
+	if len(postIDs) > 0 {
+		return postIDs, ErrMediaInUse
+	}
+
+	if err := storage.Store.Delete(ctx, media.StorageKey); err != nil {
+		return nil, err
+	}
+	return nil, database.DB.Unscoped().Delete(media).Error
+}
+
+// SignedURL 生成有時效的下載地址
+func (s *MediaService) SignedURL(ctx context.Context, media *models.Media) (string, time.Time, error) {
+	expires := config.AppConfig.SignedURLExpires
+	url, err := storage.Store.SignedURL(ctx, media.StorageKey, expires)
+	return url, time.Now().Add(expires), err
+}
+
+// Open 讀取媒體文件內容
+func (s *MediaService) Open(ctx context.Context, media *models.Media) (io.ReadCloser, error) {
+	return storage.Store.Get(ctx, media.StorageKey)
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/internal/database"
+	"backend/internal/models"
+	"backend/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// 游標方向
+const (
+	cursorNext = "next"
+	cursorPrev = "prev"
+)
+
+var ErrCursorSortMismatch = errors.New("分頁游標與當前排序不一致")
+
+// 文章列表游標：記錄排序方式與邊界文章的排序鍵
+type postCursor struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
+	Dir    string   `json:"d"`
+}
+
+// 排序方式的規範表示，用於校驗游標
+func sortKey(sort []SortField) string {
+	parts := make([]string, len(sort))
+	for i, field := range sort {
+		parts[i] = field.Field
+		if field.Desc {
+			parts[i] = "-" + parts[i]
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// 提取文章的排序鍵，時間使用 RFC3339Nano 以保留精度
+func postSortValue(post *models.Post, field string) string {
+	switch field {
+	case "id":
+		return strconv.FormatUint(uint64(post.ID), 10)
+	case "title":
+		return post.Title
+	case "created_at":
+		return post.CreatedAt.Format(time.RFC3339Nano)
+	case "updated_at":
+		return post.UpdatedAt.Format(time.RFC3339Nano)
+	case "published_at":
+		return PostPublishedTime(post).Format(time.RFC3339Nano)
+	case "view_count":
+		return strconv.Itoa(post.ViewCount)
+	}
+	return ""
+}
+
+// 將游標中的排序鍵還原為查詢參數
+func parseSortValue(field, value string) (interface{}, error) {
+	switch field {
+	case "id", "view_count":
+		return strconv.ParseInt(value, 10, 64)
+	case "created_at", "updated_at", "published_at":
+		return time.Parse(time.RFC3339Nano, value)
+	}
+	return value, nil
+}
+
+func encodePostCursor(post *models.Post, sort []SortField, dir string) (string, error) {
+	cursor := postCursor{Sort: sortKey(sort), Dir: dir}
+	for _, field := range sort {
+		cursor.Values = append(cursor.Values, postSortValue(post, field.Field))
+	}
+	return utils.EncodeCursor(cursor)
+}
+
+// 構造鍵集條件：(a > ?) OR (a = ? AND b > ?) OR ...
+// reverse 為 true 時取相反方向，用於向前翻頁
+func keysetCondition(query *gorm.DB, sort []SortField, values []interface{}, reverse bool) *gorm.DB {
+	var clauses []string
+	var args []interface{}
+	for i, field := range sort {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, sort[j].Column+" = ?")
+			args = append(args, values[j])
+		}
+		op := ">"
+		if field.Desc != reverse {
+			op = "<"
+		}
+		parts = append(parts, field.Column+" "+op+" ?")
+		args = append(args, values[i])
+		clauses = append(clauses, "("+strings.Join(parts, " AND ")+")")
+	}
+	return query.Where("("+strings.Join(clauses, " OR ")+")", args...)
+}
+
+// 反轉排序方向
+func reverseSort(sort []SortField) []SortField {
+	reversed := make([]SortField, len(sort))
+	for i, field := range sort {
+		field.Desc = !field.Desc
+		reversed[i] = field
+	}
+	return reversed
+}
+
+// GetPostsByCursor 游標分頁獲取文章列表，cursor 為空時返回第一頁
+func (s *PostService) GetPostsByCursor(cursor string, limit int, filter PostFilter, sort []SortField) (posts []models.Post, next, prev string, err error) {
+	query := preloadPost(applyPostFilter(database.DB.Model(&models.Post{}), filter))
+
+	dir := cursorNext
+	if cursor != "" {
+		var c postCursor
+		if err := utils.DecodeCursor(cursor, &c); err != nil {
+			return nil, "", "", err
+		}
+		if c.Sort != sortKey(sort) || len(c.Values) != len(sort) {
+			return nil, "", "", ErrCursorSortMismatch
+		}
+		values := make([]interface{}, len(sort))
+		for i, field := range sort {
+			if values[i], err = parseSortValue(field.Field, c.Values[i]); err != nil {
+				return nil, "", "", utils.ErrInvalidCursor
+			}
+		}
+		dir = c.Dir
+		query = keysetCondition(query, sort, values, dir == cursorPrev)
+	}
+
+	order := sort
+	if dir == cursorPrev {
+		order = reverseSort(sort)
+	}
+
+	// 多取一條用於判斷是否還有更多
+	if err := applyPostSort(query, order).Limit(limit + 1).Find(&posts).Error; err != nil {
+		return nil, "", "", err
+	}
+	hasMore := len(posts) > limit
+	if hasMore {
+		posts = posts[:limit]
+	}
+	if dir == cursorPrev {
+		for i, j := 0, len(posts)-1; i < j; i, j = i+1, j-1 {
+			posts[i], posts[j] = posts[j], posts[i]
+		}
+	}
+	if len(posts) == 0 {
+		return posts, "", "", nil
+	}
+
+	// 向後翻頁時：更多數據決定下一頁，有游標即存在上一頁；向前翻頁時相反
+	hasNext, hasPrev := hasMore, cursor != ""
+	if dir == cursorPrev {
+		hasNext, hasPrev = true, hasMore
+	}
+	if hasNext {
+		if next, err = encodePostCursor(&posts[len(posts)-1], sort, cursorNext); err != nil {
+			return nil, "", "", err
+		}
+	}
+	if hasPrev {
+		if prev, err = encodePostCursor(&posts[0], sort, cursorPrev); err != nil {
+			return nil, "", "", err
+		}
+	}
+	return posts, next, prev, nil
+}
@@ -0,0 +1,253 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/internal/database"
+	"backend/internal/models"
+	"backend/pkg/utils"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func mustParseSort(t *testing.T, sort string) []SortField {
+	t.Helper()
+	fields, err := ParsePostSort(sort)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fields
+}
+
+func TestParseSortValue(t *testing.T) {
+	created := time.Date(2024, 5, 1, 8, 30, 0, 123456789, time.FixedZone("CST", 8*3600))
+	tests := []struct {
+		field   string
+		value   string
+		want    interface{}
+		wantErr bool
+	}{
+		{"id", "42", int64(42), false},
+		{"view_count", "0", int64(0), false},
+		{"id", "abc", nil, true},
+		{"title", "Go 入門", "Go 入門", false},
+		{"title", "", "", false},
+		{"created_at", created.Format(time.RFC3339Nano), created, false},
+		{"published_at", "2024-05-01T00:00:00Z", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), false},
+		{"updated_at", "2024-05-01", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := parseSortValue(tt.field, tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSortValue(%q, %q) error = %v, wantErr %v", tt.field, tt.value, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr {
+			continue
+		}
+		if want, ok := tt.want.(time.Time); ok {
+			if !got.(time.Time).Equal(want) {
+				t.Errorf("parseSortValue(%q, %q) = %v, want %v", tt.field, tt.value, got, want)
+			}
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseSortValue(%q, %q) = %#v, want %#v", tt.field, tt.value, got, tt.want)
+		}
+	}
+}
+
+// 排序鍵經過編碼與解析後保持不變，時間保留納秒精度
+func TestPostSortValueRoundTrip(t *testing.T) {
+	published := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	post := &models.Post{Title: "標題", ViewCount: 7, PublishedAt: &published}
+	post.ID = 9
+	post.CreatedAt = time.Date(2023, 12, 31, 23, 59, 59, 999999999, time.UTC)
+
+	for _, field := range []string{"created_at", "published_at"} {
+		value, err := parseSortValue(field, postSortValue(post, field))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := post.CreatedAt
+		if field == "published_at" {
+			want = published
+		}
+		if !value.(time.Time).Equal(want) {
+			t.Errorf("%s round trip = %v, want %v", field, value, want)
+		}
+	}
+	if got := postSortValue(post, "id"); got != "9" {
+		t.Errorf("id = %q", got)
+	}
+}
+
+func TestKeysetCondition(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "dry.db")), &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		sort     string
+		values   []interface{}
+		reverse  bool
+		wantSQL  string
+		wantVars []interface{}
+	}{
+		{
+			sort:     "id",
+			values:   []interface{}{int64(5)},
+			wantSQL:  "((posts.id > ?))",
+			wantVars: []interface{}{int64(5)},
+		},
+		{
+			sort:     "-created_at",
+			values:   []interface{}{"t", int64(5)},
+			wantSQL:  "(((posts.created_at < ?) OR (posts.created_at = ? AND posts.id < ?)))",
+			wantVars: []interface{}{"t", "t", int64(5)},
+		},
+		{
+			sort:     "-created_at",
+			values:   []interface{}{"t", int64(5)},
+			reverse:  true,
+			wantSQL:  "(((posts.created_at > ?) OR (posts.created_at = ? AND posts.id > ?)))",
+			wantVars: []interface{}{"t", "t", int64(5)},
+		},
+		{
+			sort:    "-view_count,title",
+			values:  []interface{}{int64(3), "b", int64(7)},
+			wantSQL: "(((posts.view_count < ?) OR (posts.view_count = ? AND posts.title > ?) OR (posts.view_count = ? AND posts.title = ? AND posts.id > ?)))",
+			wantVars: []interface{}{
+				int64(3),
+				int64(3), "b",
+				int64(3), "b", int64(7),
+			},
+		},
+	}
+	for _, tt := range tests {
+		var posts []models.Post
+		stmt := keysetCondition(db.Model(&models.Post{}), mustParseSort(t, tt.sort), tt.values, tt.reverse).
+			Find(&posts).Statement
+		// 含 OR 的條件與軟刪除條件組合時，GORM 會在外層再包一層括號
+		sql := stmt.SQL.String()
+		where := sql[strings.Index(sql, "WHERE ")+len("WHERE "):]
+		where = strings.TrimSuffix(where, " AND `posts`.`deleted_at` IS NULL")
+		if where != tt.wantSQL {
+			t.Errorf("sort %q reverse=%v:\n got %s\nwant %s", tt.sort, tt.reverse, where, tt.wantSQL)
+		}
+		if !reflect.DeepEqual(stmt.Vars, tt.wantVars) {
+			t.Errorf("sort %q vars = %v, want %v", tt.sort, stmt.Vars, tt.wantVars)
+		}
+	}
+}
+
+// 按 -created_at 排序且存在相同創建時間時，向後翻到底再向前翻回，順序與全量排序一致且不重不漏
+func TestGetPostsByCursorWalk(t *testing.T) {
+	setupTestDB(t, &models.User{}, &models.Post{}, &models.Tag{}, &models.Category{}, &models.PostCollaborator{})
+
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	offsets := []int{0, 1, 1, 2, 3, 3, 3, 5}
+	for i, offset := range offsets {
+		post := models.Post{Title: fmt.Sprintf("post %d", i), Status: models.PostStatusPublished, AuthorID: 1}
+		post.CreatedAt = base.Add(time.Duration(offset) * time.Minute)
+		if err := database.DB.Create(&post).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// 期望順序：創建時間倒序，相同時 ID 倒序
+	want := []uint{8, 7, 6, 5, 4, 3, 2, 1}
+	sort := mustParseSort(t, "-created_at")
+	service := NewPostService()
+
+	var got []uint
+	var pages []string
+	cursor := ""
+	for i := 0; ; i++ {
+		if i > len(want) {
+			t.Fatal("too many pages")
+		}
+		posts, next, _, err := service.GetPostsByCursor(cursor, 3, PostFilter{}, sort)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, post := range posts {
+			got = append(got, post.ID)
+		}
+		pages = append(pages, cursor)
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("next walk = %v, want %v", got, want)
+	}
+
+	// 從最後一頁向前翻頁
+	posts, _, prev, err := service.GetPostsByCursor(pages[len(pages)-1], 3, PostFilter{}, sort)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back []uint
+	for _, post := range posts {
+		back = append(back, post.ID)
+	}
+	for prev != "" {
+		var page []models.Post
+		page, _, prev, err = service.GetPostsByCursor(prev, 3, PostFilter{}, sort)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := make([]uint, 0, len(page))
+		for _, post := range page {
+			ids = append(ids, post.ID)
+		}
+		back = append(ids, back...)
+	}
+	if !reflect.DeepEqual(back, want) {
+		t.Errorf("prev walk = %v, want %v", back, want)
+	}
+}
+
+func TestGetPostsByCursorRejectsMismatch(t *testing.T) {
+	setupTestDB(t, &models.User{}, &models.Post{}, &models.Tag{}, &models.Category{}, &models.PostCollaborator{})
+
+	post := &models.Post{Title: "a"}
+	post.ID = 1
+	post.CreatedAt = time.Now()
+	cursor, err := encodePostCursor(post, mustParseSort(t, "-created_at"), cursorNext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service := NewPostService()
+	if _, _, _, err := service.GetPostsByCursor(cursor, 10, PostFilter{}, mustParseSort(t, "title")); !errors.Is(err, ErrCursorSortMismatch) {
+		t.Errorf("sort mismatch error = %v, want ErrCursorSortMismatch", err)
+	}
+	if _, _, _, err := service.GetPostsByCursor(cursor, 10, PostFilter{}, mustParseSort(t, "created_at")); !errors.Is(err, ErrCursorSortMismatch) {
+		t.Errorf("direction mismatch error = %v, want ErrCursorSortMismatch", err)
+	}
+
+	// 簽名有效但排序鍵無法解析
+	bad, err := utils.EncodeCursor(postCursor{Sort: "-created_at,-id", Values: []string{"yesterday", "1"}, Dir: cursorNext})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := service.GetPostsByCursor(bad, 10, PostFilter{}, mustParseSort(t, "-created_at")); !errors.Is(err, utils.ErrInvalidCursor) {
+		t.Errorf("bad value error = %v, want ErrInvalidCursor", err)
+	}
+
+	tampered := strings.Replace(cursor, ".", "x.", 1)
+	if _, _, _, err := service.GetPostsByCursor(tampered, 10, PostFilter{}, mustParseSort(t, "-created_at")); !errors.Is(err, utils.ErrInvalidCursor) {
+		t.Errorf("tampered error = %v, want ErrInvalidCursor", err)
+	}
+}
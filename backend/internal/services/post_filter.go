@@ -0,0 +1,183 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidSortField = errors.New("不支持的排序字段")
+	ErrCategoryNotFound = errors.New("分類不存在")
+)
+
+// 默認排序：最新創建的在前
+const DefaultPostSort = "-created_at"
+
+// 允許排序的字段及對應的列
+var postSortColumns = map[string]string{
+	"id":           "posts.id",
+	"title":        "posts.title",
+	"created_at":   "posts.created_at",
+	"updated_at":   "posts.updated_at",
+	"published_at": "COALESCE(posts.published_at, posts.created_at)", // 早期文章沒有發布時間
+	"view_count":   "posts.view_count",
+}
+
+// 排序字段
+type SortField struct {
+	Field  string
+	Column string
+	Desc   bool
+}
+
+// 文章列表篩選條件，零值表示不篩選
+type PostFilter struct {
+	Status      string
+	AuthorID    uint
+	CategoryID  uint
+	TagIDs      []uint
+	MatchAllTag bool // 為 true 時需包含全部標籤，否則包含任一標籤即可
+	TitlePrefix string
+
+	// 作者或共同作者為該用戶
+	MemberID uint
+
+	// 非審核者只能看到可見範圍內的已發布文章，以及自己作為作者或協作者的文章；為 nil 時不限制
+	Reader *Actor
+
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+}
+
+// IsEmpty 是否沒有任何篩選條件
+func (f PostFilter) IsEmpty() bool {
+	return f.Status == "" && f.AuthorID == 0 && f.CategoryID == 0 && len(f.TagIDs) == 0 && f.TitlePrefix == "" && f.MemberID == 0 &&
+		f.CreatedFrom == nil && f.CreatedTo == nil && f.UpdatedFrom == nil && f.UpdatedTo == nil
+}
+
+// ParsePostSort 解析排序參數，如 "-view_count,title"，前綴 - 表示倒序
+// 未包含 id 時追加 id 作為最後的排序鍵，保證順序穩定
+func ParsePostSort(sort string) ([]SortField, error) {
+	if strings.TrimSpace(sort) == "" {
+		sort = DefaultPostSort
+	}
+
+	var fields []SortField
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(sort, ",") {
+		part = strings.TrimSpace(part)
+		desc := strings.HasPrefix(part, "-")
+		name := strings.TrimPrefix(strings.TrimPrefix(part, "-"), "+")
+
+		column, ok := postSortColumns[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q（可用字段：id、title、created_at、updated_at、published_at、view_count）", ErrInvalidSortField, name)
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		fields = append(fields, SortField{Field: name, Column: column, Desc: desc})
+	}
+
+	if !seen["id"] {
+		last := fields[len(fields)-1]
+		fields = append(fields, SortField{Field: "id", Column: postSortColumns["id"], Desc: last.Desc})
+	}
+	return fields, nil
+}
+
+// 應用篩選條件
+func applyPostFilter(query *gorm.DB, filter PostFilter) *gorm.DB {
+	if filter.Status != "" {
+		query = query.Where("posts.status = ?", filter.Status)
+	}
+	if filter.AuthorID > 0 {
+		query = query.Where("posts.author_id = ?", filter.AuthorID)
+	}
+	if filter.MemberID > 0 {
+		query = query.Where("posts.author_id = ? OR posts.id IN (?)", filter.MemberID, coAuthoredPostIDs(query, filter.MemberID))
+	}
+	if filter.Reader != nil {
+		query = readablePosts(query, *filter.Reader)
+	}
+	if filter.CategoryID > 0 {
+		query = query.Where("posts.category_id = ?", filter.CategoryID)
+	}
+	if len(filter.TagIDs) > 0 {
+		sub := query.Session(&gorm.Session{NewDB: true}).Table("post_tags").Select("post_id").
+			Where("tag_id IN ?", filter.TagIDs).Group("post_id")
+		if filter.MatchAllTag {
+			sub = sub.Having("COUNT(DISTINCT tag_id) = ?", len(uniqueIDs(filter.TagIDs)))
+		}
+		query = query.Where("posts.id IN (?)", sub)
+	}
+	if filter.TitlePrefix != "" {
+		query = query.Where("posts.title LIKE ? ESCAPE '\\'", escapeLike(filter.TitlePrefix)+"%")
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("posts.created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("posts.created_at < ?", *filter.CreatedTo)
+	}
+	if filter.UpdatedFrom != nil {
+		query = query.Where("posts.updated_at >= ?", *filter.UpdatedFrom)
+	}
+	if filter.UpdatedTo != nil {
+		query = query.Where("posts.updated_at < ?", *filter.UpdatedTo)
+	}
+	return query
+}
+
+// 應用排序
+func applyPostSort(query *gorm.DB, sort []SortField) *gorm.DB {
+	for _, field := range sort {
+		if field.Desc {
+			query = query.Order(field.Column + " DESC")
+		} else {
+			query = query.Order(field.Column + " ASC")
+		}
+	}
+	return query
+}
+
+// 轉義 LIKE 通配符
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	var result []uint
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// 校驗分類存在
+func checkCategory(tx *gorm.DB, categoryID *uint) error {
+	if categoryID == nil {
+		return nil
+	}
+	var count int64
+	if err := tx.Model(&models.Category{}).Where("id = ?", *categoryID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrCategoryNotFound
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package services
 
 import (
+	"time"
+
+	"backend/internal/cache"
 	"backend/internal/database"
 	"backend/internal/models"
 	"backend/pkg/utils"
@@ -15,24 +18,47 @@ func NewPostService() *PostService {
 }
 
 // 創建文章
-func (s *PostService) CreatePost(title, content, summary, status string, authorID uint, tagIDs []uint) (*models.Post, error) {
+func (s *PostService) CreatePost(title, content, summary, status string, authorID uint, categoryID *uint, tagIDs []uint) (*models.Post, error) {
+	if err := checkCategory(database.DB, categoryID); err != nil {
+		return nil, err
+	}
+
 	post := models.Post{
-		Title:    title,
-		Content:  content,
-		Summary:  summary,
-		Status:   status,
-		AuthorID: authorID,
+		Title:      title,
+		Content:    content,
+		Summary:    summary,
+		Status:     status,
+		AuthorID:   authorID,
+		CategoryID: categoryID,
+	}
+	if status == models.PostStatusPublished {
+		now := time.Now()
+		post.PublishedAt = &now
 	}
 
 	// 開始事務
 	tx := database.DB.Begin()
 
+	// 生成 slug
+	slug, err := uniquePostSlug(tx, title, 0)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	post.Slug = slug
+
 	// 創建文章
 	if err := tx.Create(&post).Error; err != nil {
 		tx.Rollback()
 		return nil, err
 	}
 
+	// 記錄初始狀態
+	if err := recordPostStatus(tx, post.ID, "", status, Actor{UserID: authorID}, ""); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	// 關聯標籤
 	if len(tagIDs) > 0 {
 		var tags []models.Tag
@@ -46,27 +72,22 @@ func (s *PostService) CreatePost(title, content, summary, status string, authorI
 		}
 	}
 
-	tx.Commit()
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	invalidatePosts(post.ID)
 
 	// 重新查詢包含關聯數據的文章
 	return s.GetPostByID(post.ID)
 }
 
 // 獲取文章列表
-func (s *PostService) GetPosts(page, limit int, status string, authorID uint) ([]models.Post, int64, error) {
+func (s *PostService) GetPosts(page, limit int, filter PostFilter, sort []SortField) ([]models.Post, int64, error) {
 	var posts []models.Post
 	var total int64
 
 	offset := utils.GetOffset(page, limit)
-	query := database.DB.Model(&models.Post{}).Preload("Author").Preload("Tags")
-
-	// 條件過濾
-	if status != "" {
-		query = query.Where("status = ?", status)
-	}
-	if authorID > 0 {
-		query = query.Where("author_id = ?", authorID)
-	}
+	query := applyPostFilter(database.DB.Model(&models.Post{}), filter)
 
 	// 獲取總數
 	if err := query.Count(&total).Error; err != nil {
@@ -74,38 +95,91 @@ func (s *PostService) GetPosts(page, limit int, status string, authorID uint) ([
 	}
 
 	// 獲取文章列表
-	if err := query.Offset(offset).Limit(limit).Order("created_at DESC").Find(&posts).Error; err != nil {
+	query = applyPostSort(preloadPost(query), sort)
+	if err := query.Offset(offset).Limit(limit).Find(&posts).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return posts, total, nil
 }
 
-// 根據 ID 獲取文章
+// 預加載文章的作者、共同作者、標籤與分類
+func preloadPost(query *gorm.DB) *gorm.DB {
+	return preloadCoAuthors(query.Preload("Author").Preload("Tags").Preload("Category"))
+}
+
+// 根據 ID 獲取文章，優先讀取緩存
 func (s *PostService) GetPostByID(id uint) (*models.Post, error) {
 	var post models.Post
-	if err := database.DB.Preload("Author").Preload("Tags").First(&post, id).Error; err != nil {
+	if cache.GetObject(postCacheKey(id), &post) {
+		return &post, nil
+	}
+	generation := cacheLoadStart()
+	if err := preloadPost(database.DB).First(&post, id).Error; err != nil {
 		return nil, err
 	}
+	cacheLoadDone(postCacheKey(id), generation, &post)
 	return &post, nil
 }
 
-// 更新文章
-func (s *PostService) UpdatePost(id uint, updates map[string]interface{}, tagIDs []uint) (*models.Post, error) {
+// 更新文章，updates 中的 status 會按狀態機流轉並記錄歷史
+// version 為客戶端持有的版本號，與當前版本不一致時返回 ErrVersionConflict，為 0 時不校驗
+func (s *PostService) UpdatePost(id, version uint, updates map[string]interface{}, tagIDs []uint, actor Actor) (*models.Post, error) {
 	var post models.Post
 	if err := database.DB.First(&post, id).Error; err != nil {
 		return nil, err
 	}
 
+	status, hasStatus := updates["status"].(string)
+	delete(updates, "status")
+	if hasStatus && status != post.Status {
+		if err := CheckPostStatusTransition(post.Status, status, actor); err != nil {
+			return nil, err
+		}
+	}
+
+	if categoryID, ok := updates["category_id"].(uint); ok {
+		if err := checkCategory(database.DB, &categoryID); err != nil {
+			return nil, err
+		}
+	}
+
+	title, hasTitle := updates["title"].(string)
+	titleChanged := hasTitle && title != post.Title
+
 	// 開始事務
 	tx := database.DB.Begin()
 
-	// 更新文章基本信息
-	if err := tx.Model(&post).Updates(updates).Error; err != nil {
+	// 校驗並遞增版本號，版本不一致說明文章已被其他請求修改
+	if err := bumpVersion(tx, &models.Post{}, post.ID, version); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
 
+	// 更新文章基本信息
+	if len(updates) > 0 {
+		if err := tx.Model(&post).Updates(updates).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	// 標題變更時重新生成 slug
+	if titleChanged {
+		if err := updatePostSlug(tx, &post, title); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	// 更新狀態
+	if hasStatus && status != post.Status {
+		if err := transitionPostStatus(tx, &post, status, actor, ""); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
 	// 更新標籤關聯
 	if tagIDs != nil {
 		// 清除現有標籤關聯
@@ -128,7 +202,10 @@ func (s *PostService) UpdatePost(id uint, updates map[string]interface{}, tagIDs
 		}
 	}
 
-	tx.Commit()
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	invalidatePosts(post.ID)
 
 	// 重新查詢包含關聯數據的文章
 	return s.GetPostByID(post.ID)
@@ -136,10 +213,30 @@ func (s *PostService) UpdatePost(id uint, updates map[string]interface{}, tagIDs
 
 // 刪除文章
 func (s *PostService) DeletePost(id uint) error {
-	return database.DB.Delete(&models.Post{}, id).Error
+	if err := database.DB.Delete(&models.Post{}, id).Error; err != nil {
+		return err
+	}
+	invalidatePosts(id)
+	return nil
 }
 
-// 增加瀏覽量
-func (s *PostService) IncrementViewCount(id uint) error {
-	return database.DB.Model(&models.Post{}).Where("id = ?", id).Update("view_count", gorm.Expr("view_count + ?", 1)).Error
+// 批量增加瀏覽量，counts 的鍵為文章ID
+func (s *PostService) AddViewCounts(counts map[uint]int64) error {
+	ids := make([]uint, 0, len(counts))
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		for id, n := range counts {
+			err := tx.Model(&models.Post{}).Where("id = ?", id).
+				UpdateColumn("view_count", gorm.Expr("view_count + ?", n)).Error
+			if err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	invalidatePosts(ids...)
+	return nil
 }
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"backend/internal/cache"
+	"backend/internal/database"
+	"backend/internal/models"
+	"backend/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// 標題無法生成 slug 時的前綴
+const fallbackSlug = "post"
+
+// 生成唯一 slug，與其他文章（含已刪除）及舊 slug 衝突時追加數字後綴
+func uniquePostSlug(tx *gorm.DB, title string, postID uint) (string, error) {
+	base := utils.Slugify(title)
+	if base == "" {
+		base = fallbackSlug
+	}
+
+	for i := 1; ; i++ {
+		candidate := base
+		if i > 1 {
+			candidate = fmt.Sprintf("%s-%d", base, i)
+		}
+
+		var count int64
+		if err := tx.Unscoped().Model(&models.Post{}).
+			Where("slug = ? AND id <> ?", candidate, postID).
+			Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count > 0 {
+			continue
+		}
+
+		if err := tx.Model(&models.PostSlugRedirect{}).
+			Where("slug = ? AND post_id <> ?", candidate, postID).
+			Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return candidate, nil
+		}
+	}
+}
+
+// 標題變更時更新 slug，舊 slug 保留為跳轉
+func updatePostSlug(tx *gorm.DB, post *models.Post, title string) error {
+	slug, err := uniquePostSlug(tx, title, post.ID)
+	if err != nil {
+		return err
+	}
+	if slug == post.Slug {
+		return nil
+	}
+
+	if post.Slug != "" {
+		redirect := models.PostSlugRedirect{Slug: post.Slug, PostID: post.ID}
+		if err := tx.Where(models.PostSlugRedirect{Slug: post.Slug}).FirstOrCreate(&redirect).Error; err != nil {
+			return err
+		}
+	}
+	// 改回曾經使用過的 slug 時移除對應跳轉
+	if err := tx.Where("slug = ? AND post_id = ?", slug, post.ID).Delete(&models.PostSlugRedirect{}).Error; err != nil {
+		return err
+	}
+
+	return tx.Model(post).Update("slug", slug).Error
+}
+
+// GetPostBySlug 根據 slug 獲取文章，命中舊 slug 時返回當前 slug 供跳轉
+// 緩存 slug 對應的文章ID，文章 slug 已變更時緩存失效
+func (s *PostService) GetPostBySlug(slug string) (*models.Post, string, error) {
+	var id uint
+	if cache.GetObject(postSlugCachePrefix+slug, &id) {
+		if post, err := s.GetPostByID(id); err == nil && post.Slug == slug {
+			return post, "", nil
+		}
+		cache.Store.Delete(postSlugCachePrefix + slug)
+	}
+
+	var post models.Post
+	err := database.DB.Select("id").Where("slug = ?", slug).First(&post).Error
+	if err == nil {
+		cache.SetObject(postSlugCachePrefix+slug, post.ID, 0)
+		found, err := s.GetPostByID(post.ID)
+		return found, "", err
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", err
+	}
+
+	var redirect models.PostSlugRedirect
+	if err := database.DB.Where("slug = ?", slug).First(&redirect).Error; err != nil {
+		return nil, "", err
+	}
+	if err := database.DB.Select("slug").First(&post, redirect.PostID).Error; err != nil {
+		return nil, "", err
+	}
+	return nil, post.Slug, nil
+}
+
+// EnsureSlugs 為尚未生成 slug 的舊文章補全 slug
+func (s *PostService) EnsureSlugs() error {
+	var posts []models.Post
+	if err := database.DB.Unscoped().Where("slug IS NULL OR slug = ''").Find(&posts).Error; err != nil {
+		return err
+	}
+
+	for i := range posts {
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			slug, err := uniquePostSlug(tx, posts[i].Title, posts[i].ID)
+			if err != nil {
+				return err
+			}
+			return tx.Unscoped().Model(&posts[i]).Update("slug", slug).Error
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(posts) > 0 {
+		log.Printf("已為 %d 篇文章生成 slug", len(posts))
+	}
+	return nil
+}
@@ -0,0 +1,565 @@
+package services
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"backend/internal/database"
+	"backend/internal/models"
+	"backend/pkg/markdown"
+
+	"gorm.io/gorm"
+)
+
+// 導入時單個 Markdown 文件的大小上限，防止壓縮包解壓後過大
+const MaxImportFileSize = 5 << 20
+
+// 導入結果
+const (
+	ImportCreated = "created"
+	ImportUpdated = "updated"
+	ImportFailed  = "failed"
+)
+
+var (
+	ErrImportTitleRequired = errors.New("缺少標題")
+	ErrPostInTrash         = errors.New("匹配的文章在回收站中，請先恢復")
+	ErrInvalidArchive      = errors.New("無效的 zip 壓縮包")
+)
+
+// PostDocument 導入導出使用的文章文檔，對應 Markdown 文件的 YAML 前置元數據
+// 重複導入時優先按 external_id 匹配，其次按 slug
+type PostDocument struct {
+	Title        string     `yaml:"title"`
+	Slug         string     `yaml:"slug,omitempty"`
+	ExternalID   string     `yaml:"external_id,omitempty"`
+	Status       string     `yaml:"status,omitempty"`
+	Visibility   string     `yaml:"visibility,omitempty"` // 為空時新建的文章公開，已有文章保持不變
+	VisibleRoles []string   `yaml:"visible_roles,omitempty"`
+	Author       string     `yaml:"author,omitempty"`
+	Category     string     `yaml:"category,omitempty"`
+	Tags         []string   `yaml:"tags,omitempty"`
+	Summary      string     `yaml:"summary,omitempty"`
+	CreatedAt    *time.Time `yaml:"created_at,omitempty"`
+	UpdatedAt    *time.Time `yaml:"updated_at,omitempty"`
+	PublishedAt  *time.Time `yaml:"published_at,omitempty"`
+
+	Content string `yaml:"-"`
+
+	// 別名中多出的分類，導入時只使用第一個
+	extraCategories []string
+}
+
+// Hugo、Jekyll 等靜態站點生成器使用的字段，僅在對應的標準字段缺失時生效
+type postDocumentAliases struct {
+	Date       *markdown.FrontMatterTime `yaml:"date"`    // 發佈時間
+	Lastmod    *markdown.FrontMatterTime `yaml:"lastmod"` // 最後修改時間
+	Draft      *bool                     `yaml:"draft"`
+	Categories markdown.FrontMatterList  `yaml:"categories"`
+}
+
+// ParsePostDocument 解析帶前置元數據的 Markdown 文件
+func ParsePostDocument(data []byte) (*PostDocument, error) {
+	var doc PostDocument
+	body, err := markdown.ParseFrontMatter(data, &doc)
+	if err != nil {
+		return nil, err
+	}
+	doc.Content = body
+
+	var aliases postDocumentAliases
+	if _, err := markdown.ParseFrontMatter(data, &aliases); err != nil {
+		return nil, err
+	}
+	doc.applyAliases(&aliases)
+	return &doc, nil
+}
+
+func (d *PostDocument) applyAliases(a *postDocumentAliases) {
+	if a.Draft != nil && d.Status == "" {
+		d.Status = models.PostStatusPublished
+		if *a.Draft {
+			d.Status = models.PostStatusDraft
+		}
+	}
+	if a.Date != nil {
+		if d.CreatedAt == nil {
+			d.CreatedAt = &a.Date.Time
+		}
+		if d.PublishedAt == nil && d.Status == models.PostStatusPublished {
+			d.PublishedAt = &a.Date.Time
+		}
+	}
+	if a.Lastmod != nil && d.UpdatedAt == nil {
+		d.UpdatedAt = &a.Lastmod.Time
+	}
+	if d.Category == "" {
+		var categories []string
+		for _, name := range a.Categories {
+			if name = strings.TrimSpace(name); name != "" {
+				categories = append(categories, name)
+			}
+		}
+		if len(categories) > 0 {
+			d.Category = categories[0]
+		}
+		if len(categories) > 1 {
+			d.extraCategories = categories[1:]
+		}
+	}
+}
+
+// Markdown 生成帶前置元數據的 Markdown 文件
+func (d *PostDocument) Markdown() ([]byte, error) {
+	return markdown.FormatFrontMatter(d, d.Content)
+}
+
+// 單個文件的導入結果
+type ImportFileResult struct {
+	File     string   `json:"file"`
+	Status   string   `json:"status"`
+	PostID   uint     `json:"post_id,omitempty"`
+	Slug     string   `json:"slug,omitempty"`
+	Error    string   `json:"error,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// 導入報告
+type ImportReport struct {
+	Total   int                `json:"total"`
+	Created int                `json:"created"`
+	Updated int                `json:"updated"`
+	Failed  int                `json:"failed"`
+	Files   []ImportFileResult `json:"files"`
+}
+
+func (r *ImportReport) add(result ImportFileResult) {
+	r.Total++
+	switch result.Status {
+	case ImportCreated:
+		r.Created++
+	case ImportUpdated:
+		r.Updated++
+	default:
+		r.Failed++
+	}
+	r.Files = append(r.Files, result)
+}
+
+// 按名稱查找標籤，不存在時創建，已刪除的標籤會被恢復
+func findOrCreateTag(tx *gorm.DB, name string) (*models.Tag, error) {
+	var tag models.Tag
+	err := tx.Unscoped().Where("name = ?", name).First(&tag).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		tag = models.Tag{Name: name}
+		return &tag, tx.Create(&tag).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+	if tag.DeletedAt.Valid {
+		if err := tx.Unscoped().Model(&tag).Update("deleted_at", nil).Error; err != nil {
+			return nil, err
+		}
+	}
+	return &tag, nil
+}
+
+// 按名稱查找分類，不存在時創建，已刪除的分類會被恢復
+func findOrCreateCategory(tx *gorm.DB, name string) (*models.Category, error) {
+	var category models.Category
+	err := tx.Unscoped().Where("name = ?", name).First(&category).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		category = models.Category{Name: name}
+		return &category, tx.Create(&category).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+	if category.DeletedAt.Valid {
+		if err := tx.Unscoped().Model(&category).Update("deleted_at", nil).Error; err != nil {
+			return nil, err
+		}
+	}
+	return &category, nil
+}
+
+// 查找重複導入時對應的文章，包括回收站中的文章
+func findImportedPost(tx *gorm.DB, doc *PostDocument) (*models.Post, error) {
+	var post models.Post
+	var err error
+	switch {
+	case doc.ExternalID != "":
+		err = tx.Unscoped().Where("external_id = ?", doc.ExternalID).First(&post).Error
+	case doc.Slug != "":
+		err = tx.Unscoped().Where("slug = ?", doc.Slug).First(&post).Error
+	default:
+		return nil, nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if post.DeletedAt.Valid {
+		return nil, ErrPostInTrash
+	}
+	return &post, nil
+}
+
+// ImportPost 按文檔創建或更新文章，返回文章、是否新建以及非致命的警告
+// 作者不存在時歸屬於 actor；已有文章的狀態變更遵循狀態機
+func (s *PostService) ImportPost(doc *PostDocument, actor Actor) (*models.Post, bool, []string, error) {
+	doc.Title = strings.TrimSpace(doc.Title)
+	if doc.Title == "" {
+		return nil, false, nil, ErrImportTitleRequired
+	}
+	if doc.Status == "" {
+		doc.Status = models.PostStatusDraft
+	}
+	if !IsValidPostStatus(doc.Status) {
+		return nil, false, nil, ErrInvalidPostStatus
+	}
+	if doc.Visibility != "" && !IsValidVisibility(doc.Visibility) {
+		return nil, false, nil, ErrInvalidVisibility
+	}
+	for _, role := range doc.VisibleRoles {
+		if !IsValidRole(role) {
+			return nil, false, nil, ErrInvalidVisibleRole
+		}
+	}
+
+	var warnings []string
+	if len(doc.extraCategories) > 0 {
+		warnings = append(warnings, fmt.Sprintf("文章只能屬於一個分類，已忽略 %s", strings.Join(doc.extraCategories, "、")))
+	}
+	var postID uint
+	created := false
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		existing, err := findImportedPost(tx, doc)
+		if err != nil {
+			return err
+		}
+
+		authorID := actor.UserID
+		if doc.Author != "" {
+			var author models.User
+			if err := tx.Where("username = ?", doc.Author).First(&author).Error; err == nil {
+				authorID = author.ID
+			} else if errors.Is(err, gorm.ErrRecordNotFound) {
+				warnings = append(warnings, fmt.Sprintf("作者 %q 不存在，已歸屬於當前用戶", doc.Author))
+			} else {
+				return err
+			}
+		}
+
+		var categoryID *uint
+		if doc.Category != "" {
+			category, err := findOrCreateCategory(tx, doc.Category)
+			if err != nil {
+				return err
+			}
+			categoryID = &category.ID
+		}
+
+		var tags []models.Tag
+		for _, name := range doc.Tags {
+			if name = strings.TrimSpace(name); name == "" {
+				continue
+			}
+			tag, err := findOrCreateTag(tx, name)
+			if err != nil {
+				return err
+			}
+			tags = append(tags, *tag)
+		}
+
+		// 密碼無法導入：已有文章沿用原密碼，否則改為只有作者、協作者與審核者可以查看
+		visibility := doc.Visibility
+		var roles models.StringList
+		passwordHash := ""
+		switch visibility {
+		case models.PostVisibilityRestricted:
+			roles = doc.VisibleRoles
+		case models.PostVisibilityPassword:
+			if existing != nil && existing.Visibility == models.PostVisibilityPassword && existing.PasswordHash != "" {
+				passwordHash = existing.PasswordHash
+			} else {
+				visibility = models.PostVisibilityRestricted
+				warnings = append(warnings, "密碼無法導入，文章已改為僅作者、協作者與審核者可見，請重新設置密碼")
+			}
+		}
+
+		slugSource := doc.Slug
+		if slugSource == "" {
+			slugSource = doc.Title
+		}
+
+		var post models.Post
+		if existing == nil {
+			created = true
+			post = models.Post{
+				Title:      doc.Title,
+				Content:    doc.Content,
+				Summary:    doc.Summary,
+				Status:     doc.Status,
+				AuthorID:   authorID,
+				CategoryID: categoryID,
+				ExternalID: doc.ExternalID,
+			}
+			if visibility != "" {
+				post.Visibility, post.VisibleRoles, post.PasswordHash = visibility, roles, passwordHash
+			}
+			if post.Slug, err = uniquePostSlug(tx, slugSource, 0); err != nil {
+				return err
+			}
+			if doc.Status == models.PostStatusPublished {
+				now := time.Now()
+				post.PublishedAt = &now
+			}
+			if err := tx.Create(&post).Error; err != nil {
+				return err
+			}
+			if err := recordPostStatus(tx, post.ID, "", doc.Status, actor, "導入"); err != nil {
+				return err
+			}
+		} else {
+			post = *existing
+			if err := bumpVersion(tx, &models.Post{}, post.ID, 0); err != nil {
+				return err
+			}
+			updates := map[string]interface{}{
+				"title":       doc.Title,
+				"content":     doc.Content,
+				"summary":     doc.Summary,
+				"author_id":   authorID,
+				"category_id": categoryID,
+			}
+			if doc.ExternalID != "" {
+				updates["external_id"] = doc.ExternalID
+			}
+			if visibility != "" {
+				updates["visibility"] = visibility
+				updates["visible_roles"] = nil
+				if len(roles) > 0 {
+					updates["visible_roles"] = roles
+				}
+				updates["password_hash"] = passwordHash
+			}
+			if err := tx.Model(&post).Updates(updates).Error; err != nil {
+				return err
+			}
+			if doc.Slug != "" || doc.Title != existing.Title {
+				if err := updatePostSlug(tx, &post, slugSource); err != nil {
+					return err
+				}
+			}
+			if doc.Status != post.Status {
+				if err := transitionPostStatus(tx, &post, doc.Status, actor, "導入"); err != nil {
+					return err
+				}
+			}
+		}
+
+		if doc.Slug != "" && post.Slug != doc.Slug {
+			warnings = append(warnings, fmt.Sprintf("slug %q 已被使用或無效，已改為 %q", doc.Slug, post.Slug))
+		}
+
+		if err := tx.Model(&post).Association("Tags").Replace(tags); err != nil {
+			return err
+		}
+
+		// 保留原始時間，不觸發 updated_at 自動更新；更新已有文章時 updated_at 記錄本次導入的時間
+		times := make(map[string]interface{})
+		if doc.CreatedAt != nil {
+			times["created_at"] = *doc.CreatedAt
+		}
+		if doc.PublishedAt != nil {
+			times["published_at"] = *doc.PublishedAt
+		}
+		if doc.UpdatedAt != nil && created {
+			times["updated_at"] = *doc.UpdatedAt
+		}
+		if len(times) > 0 {
+			if err := tx.Model(&post).UpdateColumns(times).Error; err != nil {
+				return err
+			}
+		}
+
+		postID = post.ID
+		return nil
+	})
+	if err != nil {
+		return nil, false, warnings, err
+	}
+	invalidatePosts(postID)
+
+	post, err := s.GetPostByID(postID)
+	return post, created, warnings, err
+}
+
+// 導入單個文件並生成結果
+func (s *PostService) importFile(name string, data []byte, actor Actor) ImportFileResult {
+	result := ImportFileResult{File: name, Status: ImportFailed}
+
+	doc, err := ParsePostDocument(data)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	post, created, warnings, err := s.ImportPost(doc, actor)
+	result.Warnings = warnings
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.PostID, result.Slug = post.ID, post.Slug
+	result.Status = ImportUpdated
+	if created {
+		result.Status = ImportCreated
+	}
+	return result
+}
+
+// 是否為需要導入的 Markdown 文件，忽略目錄、隱藏文件及 macOS 壓縮產生的元數據
+func isMarkdownFile(name string) bool {
+	base := path.Base(name)
+	if strings.HasPrefix(base, ".") || strings.HasPrefix(name, "__MACOSX/") {
+		return false
+	}
+	ext := strings.ToLower(path.Ext(base))
+	return ext == ".md" || ext == ".markdown"
+}
+
+// ImportMarkdown 導入單個 Markdown 文件
+func (s *PostService) ImportMarkdown(name string, data []byte, actor Actor) *ImportReport {
+	report := &ImportReport{Files: []ImportFileResult{}}
+	report.add(s.importFile(name, data, actor))
+	return report
+}
+
+// ImportArchive 導入 zip 壓縮包中的所有 Markdown 文件，單個文件失敗不影響其他文件
+func (s *PostService) ImportArchive(r io.ReaderAt, size int64, actor Actor) (*ImportReport, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, ErrInvalidArchive
+	}
+
+	files := make([]*zip.File, 0, len(archive.File))
+	for _, f := range archive.File {
+		if !f.FileInfo().IsDir() && isMarkdownFile(f.Name) {
+			files = append(files, f)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	report := &ImportReport{Files: []ImportFileResult{}}
+	for _, f := range files {
+		data, err := readZipFile(f)
+		if err != nil {
+			report.add(ImportFileResult{File: f.Name, Status: ImportFailed, Error: err.Error()})
+			continue
+		}
+		report.add(s.importFile(f.Name, data, actor))
+	}
+	return report, nil
+}
+
+// 讀取壓縮包中的文件，限制解壓後的大小
+func readZipFile(f *zip.File) ([]byte, error) {
+	if f.UncompressedSize64 > MaxImportFileSize {
+		return nil, fmt.Errorf("文件超過 %d MB", MaxImportFileSize>>20)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, MaxImportFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxImportFileSize {
+		return nil, fmt.Errorf("文件超過 %d MB", MaxImportFileSize>>20)
+	}
+	return data, nil
+}
+
+// 文章對應的文檔
+func newPostDocument(post *models.Post) *PostDocument {
+	doc := &PostDocument{
+		Title:        post.Title,
+		Slug:         post.Slug,
+		ExternalID:   post.ExternalID,
+		Status:       post.Status,
+		VisibleRoles: post.VisibleRoles,
+		Author:       post.Author.Username,
+		Summary:      post.Summary,
+		CreatedAt:    &post.CreatedAt,
+		UpdatedAt:    &post.UpdatedAt,
+		PublishedAt:  post.PublishedAt,
+		Content:      post.Content,
+	}
+	if post.Visibility != models.PostVisibilityPublic {
+		doc.Visibility = post.Visibility
+	}
+	if post.Category != nil {
+		doc.Category = post.Category.Name
+	}
+	for _, tag := range post.Tags {
+		doc.Tags = append(doc.Tags, tag.Name)
+	}
+	return doc
+}
+
+// ExportPosts 將符合條件的文章導出為 zip，每篇文章一個 <slug>.md 文件，返回導出數量
+func (s *PostService) ExportPosts(w io.Writer, filter PostFilter) (int, error) {
+	archive := zip.NewWriter(w)
+	count := 0
+
+	var posts []models.Post
+	err := applyPostFilter(database.DB.Model(&models.Post{}), filter).
+		Preload("Author").Preload("Tags").Preload("Category").
+		Order("posts.id ASC").
+		FindInBatches(&posts, 100, func(tx *gorm.DB, batch int) error {
+			for i := range posts {
+				post := &posts[i]
+				data, err := newPostDocument(post).Markdown()
+				if err != nil {
+					return err
+				}
+
+				name := post.Slug
+				if name == "" {
+					name = fmt.Sprintf("post-%d", post.ID)
+				}
+				f, err := archive.CreateHeader(&zip.FileHeader{
+					Name:     name + ".md",
+					Method:   zip.Deflate,
+					Modified: post.UpdatedAt,
+				})
+				if err != nil {
+					return err
+				}
+				if _, err := f.Write(data); err != nil {
+					return err
+				}
+				count++
+			}
+			return nil
+		}).Error
+	if err != nil {
+		return count, err
+	}
+	return count, archive.Close()
+}
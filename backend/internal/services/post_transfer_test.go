@@ -0,0 +1,79 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"backend/internal/models"
+)
+
+func TestParsePostDocumentAliases(t *testing.T) {
+	date := time.Date(2024, 5, 1, 10, 0, 0, 0, time.FixedZone("", 8*3600))
+	lastmod := time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC)
+	created := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		meta      string
+		status    string
+		category  string
+		extra     []string
+		created   *time.Time
+		published *time.Time
+		updated   *time.Time
+	}{
+		{
+			name:      "hugo",
+			meta:      "title: A\ndate: 2024-05-01T10:00:00+08:00\nlastmod: 2024-06-02\ndraft: false\ncategories: [Go, Web]\n",
+			status:    models.PostStatusPublished,
+			category:  "Go",
+			extra:     []string{"Web"},
+			created:   &date,
+			published: &date,
+			updated:   &lastmod,
+		},
+		{
+			name:     "jekyll",
+			meta:     "title: A\ndate: 2024-05-01 10:00:00 +0800\ncategories: go\n",
+			category: "go",
+			created:  &date,
+		},
+		{
+			name:    "hugo draft",
+			meta:    "title: A\ndate: 2024-05-01T10:00:00+08:00\ndraft: true\n",
+			status:  models.PostStatusDraft,
+			created: &date,
+		},
+		{
+			name:     "standard fields win",
+			meta:     "title: A\nstatus: archived\ncategory: Main\ncreated_at: 2023-01-01T00:00:00Z\ndate: 2024-05-01T10:00:00+08:00\ndraft: true\ncategories: [Go]\n",
+			status:   models.PostStatusArchived,
+			category: "Main",
+			created:  &created,
+		},
+	}
+	equalTime := func(a, b *time.Time) bool {
+		if a == nil || b == nil {
+			return a == b
+		}
+		return a.Equal(*b)
+	}
+	for _, tt := range tests {
+		doc, err := ParsePostDocument([]byte("---\n" + tt.meta + "---\n\nbody\n"))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if doc.Status != tt.status || doc.Category != tt.category || !reflect.DeepEqual(doc.extraCategories, tt.extra) {
+			t.Errorf("%s: status %q category %q extra %v", tt.name, doc.Status, doc.Category, doc.extraCategories)
+		}
+		if !equalTime(doc.CreatedAt, tt.created) || !equalTime(doc.PublishedAt, tt.published) || !equalTime(doc.UpdatedAt, tt.updated) {
+			t.Errorf("%s: created %v published %v updated %v", tt.name, doc.CreatedAt, doc.PublishedAt, doc.UpdatedAt)
+		}
+	}
+
+	if _, err := ParsePostDocument([]byte("---\ntitle: A\ndate: yesterday\n---\n")); err == nil {
+		t.Error("invalid date: expected error")
+	}
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"crypto/hmac"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/config"
+	"backend/internal/database"
+	"backend/internal/models"
+	"backend/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidVisibility    = errors.New("無效的可見範圍")
+	ErrInvalidVisibleRole   = errors.New("無效的可見角色")
+	ErrPostPasswordRequired = errors.New("密碼保護的文章需要設置密碼")
+	ErrPostNotProtected     = errors.New("文章未設置密碼保護")
+	ErrWrongPostPassword    = errors.New("文章密碼錯誤")
+)
+
+// IsValidVisibility 檢查可見範圍是否合法
+func IsValidVisibility(visibility string) bool {
+	switch visibility {
+	case models.PostVisibilityPublic, models.PostVisibilityMembers,
+		models.PostVisibilityRestricted, models.PostVisibilityPassword:
+		return true
+	}
+	return false
+}
+
+// SetVisibility 設置文章的可見範圍
+// restricted 時 roles 為可查看的角色，為空時只有作者、協作者與審核者可以查看
+// password 時需提供密碼，文章已受密碼保護且 password 為空時保留原密碼；修改密碼後已發放的訪問令牌失效
+func (s *PostService) SetVisibility(postID uint, visibility string, roles []string, password string) (*models.Post, error) {
+	if !IsValidVisibility(visibility) {
+		return nil, ErrInvalidVisibility
+	}
+	for _, role := range roles {
+		if !IsValidRole(role) {
+			return nil, ErrInvalidVisibleRole
+		}
+	}
+
+	var post models.Post
+	if err := database.DB.First(&post, postID).Error; err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{
+		"visibility":    visibility,
+		"visible_roles": nil,
+		"password_hash": "",
+	}
+	switch visibility {
+	case models.PostVisibilityRestricted:
+		if len(roles) > 0 {
+			updates["visible_roles"] = models.StringList(roles)
+		}
+	case models.PostVisibilityPassword:
+		switch {
+		case password != "":
+			hash, err := utils.HashPassword(password)
+			if err != nil {
+				return nil, err
+			}
+			updates["password_hash"] = hash
+		case post.Visibility == models.PostVisibilityPassword && post.PasswordHash != "":
+			updates["password_hash"] = post.PasswordHash
+		default:
+			return nil, ErrPostPasswordRequired
+		}
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := bumpVersion(tx, &models.Post{}, postID, 0); err != nil {
+			return err
+		}
+		return tx.Model(&models.Post{}).Where("id = ?", postID).Updates(updates).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	invalidatePosts(postID)
+
+	return s.GetPostByID(postID)
+}
+
+// CanViewPost 讀者能否在可見範圍內查看已發布的文章，不考慮作者與協作者身份
+// grant 為解鎖密碼保護文章後獲得的訪問令牌
+func CanViewPost(post *models.Post, reader Actor, grant string) bool {
+	switch post.Visibility {
+	case "", models.PostVisibilityPublic:
+		return true
+	case models.PostVisibilityMembers:
+		return reader.UserID != 0
+	case models.PostVisibilityRestricted:
+		if reader.UserID == 0 {
+			return false
+		}
+		for _, role := range post.VisibleRoles {
+			if role == reader.Role {
+				return true
+			}
+		}
+	case models.PostVisibilityPassword:
+		return verifyPostGrant(post, grant)
+	}
+	return false
+}
+
+// 計算訪問令牌簽名，綁定文章、過期時間與密碼，修改密碼後舊令牌失效
+func postGrantSignature(post *models.Post, expires int64) string {
+	data := "post-grant:" + strconv.FormatUint(uint64(post.ID), 10) +
+		"\n" + strconv.FormatInt(expires, 10) +
+		"\n" + post.PasswordHash
+	return hmacSHA256Hex(config.AppConfig.JWTSecret, data)
+}
+
+// 校驗訪問令牌，格式為 <過期時間戳>.<簽名>
+func verifyPostGrant(post *models.Post, grant string) bool {
+	if grant == "" || post.PasswordHash == "" {
+		return false
+	}
+	expStr, signature, ok := strings.Cut(grant, ".")
+	if !ok {
+		return false
+	}
+	expires, err := strconv.ParseInt(expStr, 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return false
+	}
+	return hmac.Equal([]byte(postGrantSignature(post, expires)), []byte(signature))
+}
+
+// UnlockPost 校驗密碼保護文章的密碼，成功時返回短期有效的訪問令牌
+func (s *PostService) UnlockPost(post *models.Post, password string) (string, time.Time, error) {
+	if post.Visibility != models.PostVisibilityPassword || post.PasswordHash == "" {
+		return "", time.Time{}, ErrPostNotProtected
+	}
+	if !utils.CheckPasswordHash(password, post.PasswordHash) {
+		return "", time.Time{}, ErrWrongPostPassword
+	}
+
+	expiresAt := time.Now().Add(config.AppConfig.PostGrantExpires).Truncate(time.Second)
+	expires := expiresAt.Unix()
+	return strconv.FormatInt(expires, 10) + "." + postGrantSignature(post, expires), expiresAt, nil
+}
+
+// 讀者可見範圍的查詢條件，密碼保護的文章需逐篇解鎖，不出現在列表中
+func visibilityCondition(reader Actor) (string, []interface{}) {
+	if reader.UserID == 0 {
+		return "posts.visibility = ?", []interface{}{models.PostVisibilityPublic}
+	}
+	return "(posts.visibility IN ? OR (posts.visibility = ? AND posts.visible_roles LIKE ?))", []interface{}{
+		[]string{models.PostVisibilityPublic, models.PostVisibilityMembers},
+		models.PostVisibilityRestricted,
+		`%"` + reader.Role + `"%`,
+	}
+}
+
+// 讀者可以看到的已發布文章，審核者不受可見範圍限制
+func visiblePublishedPosts(query *gorm.DB, reader Actor) *gorm.DB {
+	query = query.Where("posts.status = ?", models.PostStatusPublished)
+	if reader.IsReviewer() {
+		return query
+	}
+	cond, args := visibilityCondition(reader)
+	return query.Where(cond, args...)
+}
+
+// 讀者可以看到的文章：可見範圍內的已發布文章，以及自己作為作者或協作者的文章
+// 審核者可以看到全部文章
+func readablePosts(query *gorm.DB, reader Actor) *gorm.DB {
+	if reader.IsReviewer() {
+		return query
+	}
+	collaborated := query.Session(&gorm.Session{NewDB: true}).Model(&models.PostCollaborator{}).
+		Select("post_id").Where("user_id = ?", reader.UserID)
+	cond, args := visibilityCondition(reader)
+	args = append([]interface{}{models.PostStatusPublished}, args...)
+	args = append(args, reader.UserID, collaborated)
+	return query.Where("(posts.status = ? AND "+cond+") OR posts.author_id = ? OR posts.id IN (?)", args...)
+}
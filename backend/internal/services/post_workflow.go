@@ -0,0 +1,171 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"backend/internal/database"
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidPostStatus          = errors.New("無效的文章狀態")
+	ErrStatusTransitionNotAllowed = errors.New("不允許的狀態變更")
+	ErrReviewerRequired           = errors.New("需要審核權限")
+	ErrPostNotInReview            = errors.New("文章不在審核中")
+)
+
+// 狀態流轉規則，reviewerOnly 表示只有審核者可以執行
+type statusTransition struct {
+	reviewerOnly bool
+}
+
+// 文章狀態機：draft → in_review → published → archived
+var postStatusTransitions = map[string]map[string]statusTransition{
+	models.PostStatusDraft: {
+		models.PostStatusInReview:  {},
+		models.PostStatusPublished: {reviewerOnly: true},
+	},
+	models.PostStatusInReview: {
+		models.PostStatusDraft:     {},
+		models.PostStatusPublished: {reviewerOnly: true},
+	},
+	models.PostStatusPublished: {
+		models.PostStatusArchived: {},
+		models.PostStatusDraft:    {reviewerOnly: true},
+	},
+	models.PostStatusArchived: {
+		models.PostStatusDraft:     {},
+		models.PostStatusPublished: {reviewerOnly: true},
+	},
+}
+
+// IsValidPostStatus 檢查狀態是否合法
+func IsValidPostStatus(status string) bool {
+	_, ok := postStatusTransitions[status]
+	return ok
+}
+
+// CheckInitialPostStatus 檢查新建文章時的狀態
+func CheckInitialPostStatus(status string, actor Actor) error {
+	switch status {
+	case models.PostStatusDraft, models.PostStatusInReview:
+		return nil
+	case models.PostStatusPublished:
+		if !actor.IsReviewer() {
+			return ErrReviewerRequired
+		}
+		return nil
+	case models.PostStatusArchived:
+		return ErrStatusTransitionNotAllowed
+	}
+	return ErrInvalidPostStatus
+}
+
+// CheckPostStatusTransition 檢查狀態變更是否允許
+func CheckPostStatusTransition(from, to string, actor Actor) error {
+	if !IsValidPostStatus(to) {
+		return ErrInvalidPostStatus
+	}
+	rule, ok := postStatusTransitions[from][to]
+	if !ok {
+		return ErrStatusTransitionNotAllowed
+	}
+	if rule.reviewerOnly && !actor.IsReviewer() {
+		return ErrReviewerRequired
+	}
+	return nil
+}
+
+// 在事務中變更文章狀態並寫入歷史記錄
+func transitionPostStatus(tx *gorm.DB, post *models.Post, to string, actor Actor, comment string) error {
+	from := post.Status
+	if err := CheckPostStatusTransition(from, to, actor); err != nil {
+		return err
+	}
+
+	updates := map[string]interface{}{"status": to}
+	if to == models.PostStatusPublished && post.PublishedAt == nil {
+		now := time.Now()
+		updates["published_at"] = &now
+	}
+	if err := tx.Model(post).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	return recordPostStatus(tx, post.ID, from, to, actor, comment)
+}
+
+// 寫入狀態歷史
+func recordPostStatus(tx *gorm.DB, postID uint, from, to string, actor Actor, comment string) error {
+	history := models.PostStatusHistory{
+		PostID:     postID,
+		FromStatus: from,
+		ToStatus:   to,
+		ActorID:    actor.UserID,
+		Comment:    comment,
+	}
+	return tx.Create(&history).Error
+}
+
+// ChangePostStatus 變更文章狀態
+func (s *PostService) ChangePostStatus(id uint, to string, actor Actor, comment string) (*models.Post, error) {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var post models.Post
+		if err := tx.First(&post, id).Error; err != nil {
+			return err
+		}
+		if err := bumpVersion(tx, &models.Post{}, post.ID, 0); err != nil {
+			return err
+		}
+		return transitionPostStatus(tx, &post, to, actor, comment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	invalidatePosts(id)
+	return s.GetPostByID(id)
+}
+
+// ReviewPost 審核文章，approve 為 true 時發布，否則退回草稿
+func (s *PostService) ReviewPost(id uint, approve bool, actor Actor, comment string) (*models.Post, error) {
+	if !actor.IsReviewer() {
+		return nil, ErrReviewerRequired
+	}
+
+	to := models.PostStatusDraft
+	if approve {
+		to = models.PostStatusPublished
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var post models.Post
+		if err := tx.First(&post, id).Error; err != nil {
+			return err
+		}
+		if post.Status != models.PostStatusInReview {
+			return ErrPostNotInReview
+		}
+		if err := bumpVersion(tx, &models.Post{}, post.ID, 0); err != nil {
+			return err
+		}
+		return transitionPostStatus(tx, &post, to, actor, comment)
+	})
+	if err != nil {
+		return nil, err
+	}
+	invalidatePosts(id)
+	return s.GetPostByID(id)
+}
+
+// GetPostStatusHistory 獲取文章狀態歷史
+func (s *PostService) GetPostStatusHistory(id uint) ([]models.PostStatusHistory, error) {
+	var history []models.PostStatusHistory
+	err := database.DB.Preload("Actor").
+		Where("post_id = ?", id).
+		Order("created_at ASC, id ASC").
+		Find(&history).Error
+	return history, err
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"crypto/hmac"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/config"
+	"backend/internal/database"
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidPreviewToken = errors.New("預覽鏈接無效或已過期")
+	ErrPreviewNotFound     = errors.New("預覽鏈接不存在")
+	ErrPreviewExpiresRange = errors.New("有效期超出允許範圍")
+)
+
+type PreviewService struct {
+	postService *PostService
+}
+
+func NewPreviewService() *PreviewService {
+	return &PreviewService{postService: NewPostService()}
+}
+
+// 計算預覽令牌簽名，令牌綁定鏈接 ID、文章 ID 與過期時間
+func previewSignature(id, postID uint, expires int64) string {
+	data := "preview:" + strconv.FormatUint(uint64(id), 10) +
+		"\n" + strconv.FormatUint(uint64(postID), 10) +
+		"\n" + strconv.FormatInt(expires, 10)
+	return hmacSHA256Hex(config.AppConfig.JWTSecret, data)
+}
+
+// 填充令牌與預覽地址，格式為 <id>.<過期時間戳>.<簽名>
+func fillPreviewToken(preview *models.PreviewToken) {
+	expires := preview.ExpiresAt.Unix()
+	preview.Token = strconv.FormatUint(uint64(preview.ID), 10) + "." +
+		strconv.FormatInt(expires, 10) + "." +
+		previewSignature(preview.ID, preview.PostID, expires)
+	preview.URL = config.AppConfig.SiteURL + "/api/preview/" + url.PathEscape(preview.Token)
+}
+
+// CreatePreview 為文章生成預覽鏈接，expires 為 0 時使用默認有效期
+func (s *PreviewService) CreatePreview(postID, userID uint, expires time.Duration) (*models.PreviewToken, error) {
+	if expires == 0 {
+		expires = config.AppConfig.PreviewExpires
+	}
+	if expires < time.Minute || expires > config.AppConfig.PreviewMaxExpires {
+		return nil, ErrPreviewExpiresRange
+	}
+
+	preview := models.PreviewToken{
+		PostID:    postID,
+		CreatedBy: userID,
+		// 簽名按秒計算，過期時間截斷到秒，保證讀回後簽名一致
+		ExpiresAt: time.Now().Add(expires).Truncate(time.Second),
+	}
+	if err := database.DB.Create(&preview).Error; err != nil {
+		return nil, err
+	}
+	fillPreviewToken(&preview)
+	return &preview, nil
+}
+
+// GetPreviews 獲取文章的預覽鏈接，包括已過期與已撤銷的
+func (s *PreviewService) GetPreviews(postID uint) ([]models.PreviewToken, error) {
+	var previews []models.PreviewToken
+	if err := database.DB.Where("post_id = ?", postID).
+		Order("created_at DESC, id DESC").
+		Find(&previews).Error; err != nil {
+		return nil, err
+	}
+	for i := range previews {
+		if previews[i].RevokedAt == nil {
+			fillPreviewToken(&previews[i])
+		}
+	}
+	return previews, nil
+}
+
+// RevokePreview 撤銷預覽鏈接，撤銷後令牌立即失效
+func (s *PreviewService) RevokePreview(postID, previewID uint) error {
+	result := database.DB.Model(&models.PreviewToken{}).
+		Where("id = ? AND post_id = ? AND revoked_at IS NULL", previewID, postID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPreviewNotFound
+	}
+	return nil
+}
+
+// ResolvePreview 校驗令牌並返回對應的文章
+// 簽名錯誤、已過期、已撤銷或文章已刪除時返回 ErrInvalidPreviewToken
+func (s *PreviewService) ResolvePreview(token string) (*models.Post, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidPreviewToken
+	}
+	id, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return nil, ErrInvalidPreviewToken
+	}
+	expires, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return nil, ErrInvalidPreviewToken
+	}
+
+	var preview models.PreviewToken
+	if err := database.DB.First(&preview, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidPreviewToken
+		}
+		return nil, err
+	}
+	if preview.RevokedAt != nil || preview.ExpiresAt.Unix() != expires ||
+		!hmac.Equal([]byte(previewSignature(preview.ID, preview.PostID, expires)), []byte(parts[2])) {
+		return nil, ErrInvalidPreviewToken
+	}
+
+	post, err := s.postService.GetPostByID(preview.PostID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidPreviewToken
+		}
+		return nil, err
+	}
+	return post, nil
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"errors"
+
+	"backend/internal/database"
+	"backend/internal/models"
+
+	"gorm.io/gorm/clause"
+)
+
+var ErrInvalidReactionType = errors.New("不支持的表情類型")
+
+// 支持的表情回應
+var ReactionTypes = map[string]string{
+	"like":      "👍",
+	"love":      "❤️",
+	"laugh":     "😂",
+	"wow":       "😮",
+	"sad":       "😢",
+	"celebrate": "🎉",
+}
+
+type ReactionService struct{}
+
+func NewReactionService() *ReactionService {
+	return &ReactionService{}
+}
+
+// 添加表情回應，重複添加不會報錯
+func (s *ReactionService) AddReaction(postID, userID uint, reactionType string) error {
+	if _, ok := ReactionTypes[reactionType]; !ok {
+		return ErrInvalidReactionType
+	}
+	if err := database.DB.First(&models.Post{}, postID).Error; err != nil {
+		return err
+	}
+
+	reaction := models.Reaction{
+		PostID: postID,
+		UserID: userID,
+		Type:   reactionType,
+	}
+	return database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&reaction).Error
+}
+
+// 移除表情回應，不存在時同樣視為成功
+func (s *ReactionService) RemoveReaction(postID, userID uint, reactionType string) error {
+	if _, ok := ReactionTypes[reactionType]; !ok {
+		return ErrInvalidReactionType
+	}
+	return database.DB.
+		Where("post_id = ? AND user_id = ? AND type = ?", postID, userID, reactionType).
+		Delete(&models.Reaction{}).Error
+}
+
+// 統計文章各表情數量，忽略已刪除用戶的回應
+func (s *ReactionService) GetReactionCounts(postID uint) (map[string]int64, error) {
+	var rows []struct {
+		Type  string
+		Count int64
+	}
+	err := database.DB.Model(&models.Reaction{}).
+		Select("reactions.type, COUNT(*) AS count").
+		Joins("JOIN users ON users.id = reactions.user_id AND users.deleted_at IS NULL").
+		Where("reactions.post_id = ?", postID).
+		Group("reactions.type").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Type] = row.Count
+	}
+	return counts, nil
+}
+
+// 獲取用戶對文章的表情回應
+func (s *ReactionService) GetUserReactions(postID, userID uint) ([]string, error) {
+	var types []string
+	err := database.DB.Model(&models.Reaction{}).
+		Where("post_id = ? AND user_id = ?", postID, userID).
+		Order("type").
+		Pluck("type", &types).Error
+	return types, err
+}
@@ -0,0 +1,387 @@
+package services
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"backend/internal/database"
+	"backend/internal/models"
+	"backend/pkg/markdown"
+	"backend/pkg/tfidf"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 相關文章最多返回的數量
+const MaxRelatedPosts = 20
+
+const (
+	// 綜合得分中各項的權重，三項得分都在 0 到 1 之間
+	relatedTextWeight     = 0.5
+	relatedTagWeight      = 0.35
+	relatedCategoryWeight = 0.15
+
+	// 按標籤與分類召回的候選文章數上限
+	relatedCandidateLimit = 100
+
+	// 每篇文章保留的相似文章數
+	similarityKeep = 2 * MaxRelatedPosts
+	// 低於此值的文本相似度不保存
+	minSimilarity = 0.02
+	// 計算相似度時只使用權重最高的若干個詞
+	maxQueryTerms = 100
+	// 每批重新索引的文章數
+	relatedIndexBatch = 50
+	// IN 查詢每批的參數個數
+	termChunkSize = 500
+)
+
+// RelatedPost 相關文章及其綜合得分
+type RelatedPost struct {
+	Post  models.Post `json:"post"`
+	Score float64     `json:"score"`
+}
+
+type RelatedService struct{}
+
+// 同一時間只運行一個索引任務
+var relatedIndexMu sync.Mutex
+
+func NewRelatedService() *RelatedService {
+	return &RelatedService{}
+}
+
+// GetRelated 獲取讀者可以看到的相關文章，按標籤重合度、是否同分類與預計算的文本相似度綜合排序
+// excludeAuthor 為 true 時排除與當前文章同一作者的文章
+func (s *RelatedService) GetRelated(post *models.Post, reader Actor, limit int, excludeAuthor bool) ([]RelatedPost, error) {
+	scores := make(map[uint]float64)
+
+	// 文本相似度
+	var similar []models.PostSimilarity
+	if err := database.DB.Where("post_id = ?", post.ID).Find(&similar).Error; err != nil {
+		return nil, err
+	}
+	for _, sim := range similar {
+		scores[sim.RelatedID] += relatedTextWeight * sim.Score
+	}
+
+	// 按標籤與分類召回的候選限定在讀者可見的文章中，避免不可見的文章佔滿候選名額
+	candidates := func() *gorm.DB {
+		query := visiblePublishedPosts(database.DB.Model(&models.Post{}), reader).
+			Where("posts.id <> ?", post.ID)
+		if excludeAuthor {
+			query = query.Where("posts.author_id <> ?", post.AuthorID)
+		}
+		return query
+	}
+
+	// 共同標籤，按當前文章的標籤數歸一化
+	if len(post.Tags) > 0 {
+		tagIDs := make([]uint, len(post.Tags))
+		for i, tag := range post.Tags {
+			tagIDs[i] = tag.ID
+		}
+		var shared []struct {
+			PostID uint
+			Count  int
+		}
+		if err := database.DB.Table("post_tags").
+			Select("post_id, COUNT(DISTINCT tag_id) AS count").
+			Where("tag_id IN ? AND post_id IN (?)", tagIDs, candidates().Select("posts.id")).
+			Group("post_id").Order("count DESC, post_id DESC").
+			Limit(relatedCandidateLimit).
+			Scan(&shared).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range shared {
+			scores[row.PostID] += relatedTagWeight * float64(row.Count) / float64(len(tagIDs))
+		}
+	}
+
+	// 同一分類，優先最近發布的文章
+	if post.CategoryID != nil {
+		var ids []uint
+		if err := candidates().
+			Where("posts.category_id = ?", *post.CategoryID).
+			Order("COALESCE(posts.published_at, posts.created_at) DESC").
+			Limit(relatedCandidateLimit).
+			Pluck("posts.id", &ids).Error; err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			scores[id] += relatedCategoryWeight
+		}
+	}
+
+	if len(scores) == 0 {
+		return []RelatedPost{}, nil
+	}
+	ids := make([]uint, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+
+	query := visiblePublishedPosts(preloadPost(database.DB.Model(&models.Post{})), reader).
+		Where("posts.id IN ?", ids)
+	if excludeAuthor {
+		query = query.Where("posts.author_id <> ?", post.AuthorID)
+	}
+	var posts []models.Post
+	if err := query.Find(&posts).Error; err != nil {
+		return nil, err
+	}
+
+	related := make([]RelatedPost, len(posts))
+	for i := range posts {
+		related[i] = RelatedPost{Post: posts[i], Score: math.Round(scores[posts[i].ID]*1000) / 1000}
+	}
+	sort.SliceStable(related, func(i, j int) bool {
+		if related[i].Score != related[j].Score {
+			return related[i].Score > related[j].Score
+		}
+		return PostPublishedTime(&related[i].Post).After(PostPublishedTime(&related[j].Post))
+	})
+	if len(related) > limit {
+		related = related[:limit]
+	}
+	return related, nil
+}
+
+// IndexPending 增量更新文本索引：移除已刪除或未發布的文章，重新索引新增或更新過的已發布文章
+// 上一輪尚未結束時直接返回
+func (s *RelatedService) IndexPending(ctx context.Context) error {
+	if !relatedIndexMu.TryLock() {
+		return nil
+	}
+	defer relatedIndexMu.Unlock()
+
+	if err := s.removeStale(); err != nil {
+		return err
+	}
+
+	// 按 ID 順序分批處理，每輪每篇文章最多索引一次
+	// 按版本號判斷文章是否更新過，導入等操作會改寫 updated_at，不能用時間比較；
+	// 版本號與內容一同讀取，索引期間再次更新的文章下一輪會重新索引
+	var lastID uint
+	for ctx.Err() == nil {
+		var posts []models.Post
+		if err := database.DB.Model(&models.Post{}).
+			Select("posts.id, posts.title, posts.content, posts.version").
+			Joins("LEFT JOIN post_text_indices ON post_text_indices.post_id = posts.id").
+			Where("posts.id > ? AND posts.status = ?", lastID, models.PostStatusPublished).
+			Where("post_text_indices.post_id IS NULL OR posts.version <> post_text_indices.indexed_version").
+			Order("posts.id").Limit(relatedIndexBatch).
+			Find(&posts).Error; err != nil {
+			return err
+		}
+		if len(posts) == 0 {
+			return nil
+		}
+		lastID = posts[len(posts)-1].ID
+
+		// 先寫入整批文章的詞頻，再計算相似度，批內文章之間也能互相匹配
+		for i := range posts {
+			if err := s.indexTerms(&posts[i]); err != nil {
+				return err
+			}
+		}
+		for i := range posts {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := s.updateSimilarities(posts[i].ID); err != nil {
+				return err
+			}
+		}
+	}
+	return ctx.Err()
+}
+
+// 移除不再是已發布狀態的文章的索引，以及指向已移除文章的相似度
+func (s *RelatedService) removeStale() error {
+	var stale []uint
+	if err := database.DB.Model(&models.PostTextIndex{}).
+		Joins("LEFT JOIN posts ON posts.id = post_text_indices.post_id AND posts.deleted_at IS NULL AND posts.status = ?", models.PostStatusPublished).
+		Where("posts.id IS NULL").
+		Pluck("post_text_indices.post_id", &stale).Error; err != nil {
+		return err
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if len(stale) > 0 {
+			if err := tx.Where("post_id IN ?", stale).Delete(&models.PostTerm{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("post_id IN ?", stale).Delete(&models.PostTextIndex{}).Error; err != nil {
+				return err
+			}
+		}
+		indexed := tx.Session(&gorm.Session{NewDB: true}).Model(&models.PostTextIndex{}).Select("post_id")
+		return tx.Where("post_id NOT IN (?) OR related_id NOT IN (?)", indexed, indexed).
+			Delete(&models.PostSimilarity{}).Error
+	})
+}
+
+// 重新統計文章詞頻，標題計兩次以提高權重
+func (s *RelatedService) indexTerms(post *models.Post) error {
+	counts := tfidf.Terms(post.Title + "\n" + post.Title + "\n" + markdown.PlainText(post.Content))
+	terms := make([]models.PostTerm, 0, len(counts))
+	for term, count := range counts {
+		terms = append(terms, models.PostTerm{PostID: post.ID, Term: term, Count: count})
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("post_id = ?", post.ID).Delete(&models.PostTerm{}).Error; err != nil {
+			return err
+		}
+		if len(terms) > 0 {
+			if err := tx.CreateInBatches(terms, termChunkSize).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "post_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"indexed_version", "indexed_at"}),
+		}).Create(&models.PostTextIndex{PostID: post.ID, IndexedVersion: post.Version, IndexedAt: time.Now()}).Error
+	})
+}
+
+// 計算文章與其他文章的 TF-IDF 餘弦相似度，更新雙方的相似文章列表
+// 其他文章的向量長度取自各自索引時的結果，逆文檔頻率隨文章增減略有偏差，在文章下次更新時修正
+func (s *RelatedService) updateSimilarities(postID uint) error {
+	var own []models.PostTerm
+	if err := database.DB.Where("post_id = ?", postID).Find(&own).Error; err != nil {
+		return err
+	}
+
+	var docs int64
+	if err := database.DB.Model(&models.PostTextIndex{}).Count(&docs).Error; err != nil {
+		return err
+	}
+
+	// 文檔頻率
+	termList := make([]string, len(own))
+	for i, t := range own {
+		termList[i] = t.Term
+	}
+	docFreq := make(map[string]int64, len(own))
+	for _, chunk := range chunkStrings(termList, termChunkSize) {
+		var rows []struct {
+			Term  string
+			Count int64
+		}
+		if err := database.DB.Model(&models.PostTerm{}).
+			Select("term, COUNT(*) AS count").
+			Where("term IN ?", chunk).Group("term").
+			Scan(&rows).Error; err != nil {
+			return err
+		}
+		for _, row := range rows {
+			docFreq[row.Term] = row.Count
+		}
+	}
+
+	// 當前文章的向量，只用權重最高的詞查找候選文章
+	weights := make(map[string]float64, len(own))
+	var norm float64
+	for _, t := range own {
+		w := tfidf.Weight(t.Count, docFreq[t.Term], docs)
+		weights[t.Term] = w
+		norm += w * w
+	}
+	norm = math.Sqrt(norm)
+	sort.Slice(termList, func(i, j int) bool { return weights[termList[i]] > weights[termList[j]] })
+	if len(termList) > maxQueryTerms {
+		termList = termList[:maxQueryTerms]
+	}
+
+	scores := make(map[uint]float64)
+	if norm > 0 && len(termList) > 0 {
+		var rows []models.PostTerm
+		if err := database.DB.Where("term IN ? AND post_id <> ?", termList, postID).Find(&rows).Error; err != nil {
+			return err
+		}
+		dots := make(map[uint]float64)
+		for _, row := range rows {
+			dots[row.PostID] += weights[row.Term] * tfidf.Weight(row.Count, docFreq[row.Term], docs)
+		}
+
+		ids := make([]uint, 0, len(dots))
+		for id := range dots {
+			ids = append(ids, id)
+		}
+		var norms []models.PostTextIndex
+		if len(ids) > 0 {
+			if err := database.DB.Where("post_id IN ?", ids).Find(&norms).Error; err != nil {
+				return err
+			}
+		}
+		for _, other := range norms {
+			if other.Norm <= 0 {
+				continue
+			}
+			if score := dots[other.PostID] / (norm * other.Norm); score >= minSimilarity {
+				scores[other.PostID] = math.Min(score, 1)
+			}
+		}
+	}
+
+	// 當前文章保留得分最高的若干篇
+	ranked := make([]models.PostSimilarity, 0, len(scores))
+	for id, score := range scores {
+		ranked = append(ranked, models.PostSimilarity{PostID: postID, RelatedID: id, Score: score})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].RelatedID > ranked[j].RelatedID
+	})
+	if len(ranked) > similarityKeep {
+		ranked = ranked[:similarityKeep]
+	}
+
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.PostTextIndex{}).Where("post_id = ?", postID).Update("norm", norm).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("post_id = ? OR related_id = ?", postID, postID).Delete(&models.PostSimilarity{}).Error; err != nil {
+			return err
+		}
+		if len(ranked) > 0 {
+			if err := tx.Create(&ranked).Error; err != nil {
+				return err
+			}
+		}
+
+		// 寫入對方的列表後只保留對方得分最高的若干篇
+		for id, score := range scores {
+			if err := tx.Create(&models.PostSimilarity{PostID: id, RelatedID: postID, Score: score}).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec(`DELETE FROM post_similarities WHERE post_id = ? AND id NOT IN
+				(SELECT id FROM post_similarities WHERE post_id = ? ORDER BY score DESC, related_id DESC LIMIT ?)`,
+				id, id, similarityKeep).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// 將字符串列表按大小分批
+func chunkStrings(list []string, size int) [][]string {
+	var chunks [][]string
+	for len(list) > size {
+		chunks = append(chunks, list[:size])
+		list = list[size:]
+	}
+	if len(list) > 0 {
+		chunks = append(chunks, list)
+	}
+	return chunks
+}
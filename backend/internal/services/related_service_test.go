@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"backend/internal/database"
+	"backend/internal/models"
+)
+
+func relatedTestModels() []interface{} {
+	return []interface{}{
+		&models.User{}, &models.Post{}, &models.Tag{}, &models.Category{}, &models.PostCollaborator{},
+		&models.PostTerm{}, &models.PostTextIndex{}, &models.PostSimilarity{},
+	}
+}
+
+// 不可見的文章不佔用按標籤與分類召回的候選名額
+func TestGetRelatedCandidatesRespectVisibility(t *testing.T) {
+	setupTestDB(t, relatedTestModels()...)
+
+	tag := models.Tag{Name: "go"}
+	category := models.Category{Name: "dev"}
+	database.DB.Create(&tag)
+	database.DB.Create(&category)
+
+	newPost := func(title, visibility string) models.Post {
+		post := models.Post{
+			Title: title, Status: models.PostStatusPublished, Visibility: visibility,
+			AuthorID: 1, CategoryID: &category.ID, Tags: []models.Tag{tag},
+		}
+		if err := database.DB.Create(&post).Error; err != nil {
+			t.Fatal(err)
+		}
+		return post
+	}
+	current := newPost("current", models.PostVisibilityPublic)
+	visible := newPost("visible", models.PostVisibilityPublic)
+	// ID 更大、發布時間更近的受限文章排在候選前面
+	for i := 0; i < relatedCandidateLimit+10; i++ {
+		newPost(fmt.Sprintf("restricted %d", i), models.PostVisibilityRestricted)
+	}
+
+	reader := Actor{UserID: 99, Role: RoleUser}
+	related, err := NewRelatedService().GetRelated(&current, reader, 5, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(related) != 1 || related[0].Post.ID != visible.ID {
+		t.Fatalf("related = %+v, want only post %d", related, visible.ID)
+	}
+	// 共同標籤與同一分類都計入
+	if want := relatedTagWeight + relatedCategoryWeight; related[0].Score != want {
+		t.Errorf("score = %v, want %v", related[0].Score, want)
+	}
+
+	// 排除同一作者時同樣在候選查詢中過濾
+	related, err = NewRelatedService().GetRelated(&current, reader, 5, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(related) != 0 {
+		t.Errorf("exclude author: related = %+v, want none", related)
+	}
+}
+
+// 按版本號判斷是否需要重新索引，updated_at 被改寫為更早的時間也不影響
+func TestIndexPendingUsesVersion(t *testing.T) {
+	setupTestDB(t, relatedTestModels()...)
+
+	post := models.Post{Title: "golang generics", Content: "type parameters", Status: models.PostStatusPublished, AuthorID: 1}
+	if err := database.DB.Create(&post).Error; err != nil {
+		t.Fatal(err)
+	}
+	service := NewRelatedService()
+	ctx := context.Background()
+	if err := service.IndexPending(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var index models.PostTextIndex
+	if err := database.DB.First(&index, "post_id = ?", post.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if index.IndexedVersion != post.Version {
+		t.Fatalf("indexed version = %d, want %d", index.IndexedVersion, post.Version)
+	}
+
+	// 與導入相同：更新內容並遞增版本號，同時把 updated_at 改寫為過去的時間
+	past := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	version := post.Version + 1
+	if err := database.DB.Model(&post).UpdateColumns(map[string]interface{}{
+		"content":    "rust ownership",
+		"version":    version,
+		"updated_at": past,
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := service.IndexPending(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var terms []string
+	database.DB.Model(&models.PostTerm{}).Where("post_id = ?", post.ID).Pluck("term", &terms)
+	has := make(map[string]bool)
+	for _, term := range terms {
+		has[term] = true
+	}
+	if !has["rust"] || has["type"] {
+		t.Errorf("terms after update = %v, want reindexed content", terms)
+	}
+	database.DB.First(&index, "post_id = ?", post.ID)
+	if index.IndexedVersion != version {
+		t.Errorf("indexed version = %d, want %d", index.IndexedVersion, version)
+	}
+}
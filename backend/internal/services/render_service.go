@@ -0,0 +1,74 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"backend/internal/database"
+	"backend/internal/models"
+	"backend/pkg/markdown"
+
+	"gorm.io/gorm/clause"
+)
+
+// 摘要最大字數
+const SummaryLength = 200
+
+type RenderService struct{}
+
+func NewRenderService() *RenderService {
+	return &RenderService{}
+}
+
+// 內容哈希，作為渲染緩存的版本號
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// 獲取文章渲染結果，當前版本未渲染時渲染並緩存
+func (s *RenderService) GetRendered(post *models.Post) (*models.PostRender, error) {
+	hash := contentHash(post.Content)
+
+	var render models.PostRender
+	err := database.DB.Where("post_id = ? AND content_hash = ?", post.ID, hash).Limit(1).Find(&render).Error
+	if err != nil {
+		return nil, err
+	}
+	if render.ID != 0 {
+		return &render, nil
+	}
+
+	result, err := markdown.Render(post.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	toc := make(models.TOC, len(result.TOC))
+	for i, heading := range result.TOC {
+		toc[i] = models.TOCEntry{Level: heading.Level, Text: heading.Text, ID: heading.ID}
+	}
+	render = models.PostRender{
+		PostID:      post.ID,
+		ContentHash: hash,
+		HTML:        result.HTML,
+		TOC:         toc,
+		WordCount:   result.WordCount,
+		ReadingTime: result.ReadingTime,
+	}
+
+	// 舊版本不再需要
+	if err := database.DB.Where("post_id = ? AND content_hash <> ?", post.ID, hash).Delete(&models.PostRender{}).Error; err != nil {
+		return nil, err
+	}
+	if err := database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&render).Error; err != nil {
+		return nil, err
+	}
+
+	return &render, nil
+}
+
+// GenerateSummary 從 Markdown 內容生成純文本摘要
+func GenerateSummary(content string) string {
+	return markdown.Summary(content, SummaryLength)
+}
@@ -0,0 +1,312 @@
+package services
+
+import (
+	"errors"
+
+	"backend/internal/database"
+	"backend/internal/models"
+	"backend/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSeriesNotFound      = errors.New("系列不存在")
+	ErrPostInOtherSeries   = errors.New("文章已屬於其他系列")
+	ErrPostNotInSeries     = errors.New("文章不在此系列中")
+	ErrSeriesOrderMismatch = errors.New("排序列表必須包含系列中的全部文章且不能重複")
+)
+
+type SeriesService struct{}
+
+func NewSeriesService() *SeriesService {
+	return &SeriesService{}
+}
+
+// 創建系列
+func (s *SeriesService) CreateSeries(title, description string, authorID uint) (*models.Series, error) {
+	series := models.Series{
+		Title:       title,
+		Description: description,
+		AuthorID:    authorID,
+	}
+	if err := database.DB.Create(&series).Error; err != nil {
+		return nil, err
+	}
+	return s.GetSeriesByID(series.ID)
+}
+
+// 根據 ID 獲取系列
+func (s *SeriesService) GetSeriesByID(id uint) (*models.Series, error) {
+	var series models.Series
+	if err := database.DB.Preload("Author").First(&series, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSeriesNotFound
+		}
+		return nil, err
+	}
+	return &series, nil
+}
+
+// 獲取系列列表，authorID 不為 0 時只返回該作者的系列
+// 文章數只統計讀者可以看到的已發布文章
+func (s *SeriesService) GetSeriesList(page, limit int, authorID uint, reader Actor) ([]models.Series, int64, error) {
+	var list []models.Series
+	var total int64
+
+	query := database.DB.Model(&models.Series{})
+	if authorID > 0 {
+		query = query.Where("author_id = ?", authorID)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Preload("Author").
+		Order("created_at DESC, id DESC").
+		Offset(utils.GetOffset(page, limit)).Limit(limit).
+		Find(&list).Error; err != nil {
+		return nil, 0, err
+	}
+	if len(list) == 0 {
+		return list, total, nil
+	}
+
+	// 統計各系列讀者可見的文章數
+	ids := make([]uint, len(list))
+	for i := range list {
+		ids[i] = list[i].ID
+	}
+	var counts []struct {
+		SeriesID uint
+		Count    int64
+	}
+	if err := publishedSeriesPosts(database.DB, reader).
+		Select("series_posts.series_id, COUNT(*) AS count").
+		Where("series_posts.series_id IN ?", ids).
+		Group("series_posts.series_id").
+		Scan(&counts).Error; err != nil {
+		return nil, 0, err
+	}
+	countByID := make(map[uint]int64, len(counts))
+	for _, c := range counts {
+		countByID[c.SeriesID] = c.Count
+	}
+	for i := range list {
+		list[i].PostCount = countByID[list[i].ID]
+	}
+	return list, total, nil
+}
+
+// 更新系列基本信息
+func (s *SeriesService) UpdateSeries(id uint, updates map[string]interface{}) (*models.Series, error) {
+	if len(updates) > 0 {
+		if err := database.DB.Model(&models.Series{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+	return s.GetSeriesByID(id)
+}
+
+// 刪除系列，其中的文章不受影響，可加入其他系列
+func (s *SeriesService) DeleteSeries(id uint) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("series_id = ?", id).Delete(&models.SeriesPost{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Series{}, id).Error
+	})
+}
+
+// 讀者可見範圍內已發布且未刪除的系列文章
+func publishedSeriesPosts(db *gorm.DB, reader Actor) *gorm.DB {
+	return visiblePublishedPosts(db.Model(&models.SeriesPost{}).
+		Joins("JOIN posts ON posts.id = series_posts.post_id AND posts.deleted_at IS NULL"), reader)
+}
+
+// GetSeriesPosts 按順序獲取系列中的文章，reader 不為 nil 時只返回該讀者可以看到的已發布文章
+func (s *SeriesService) GetSeriesPosts(seriesID uint, reader *Actor) ([]models.SeriesPost, error) {
+	query := database.DB.Model(&models.SeriesPost{}).
+		Joins("JOIN posts ON posts.id = series_posts.post_id AND posts.deleted_at IS NULL")
+	if reader != nil {
+		query = publishedSeriesPosts(database.DB, *reader)
+	}
+
+	var parts []models.SeriesPost
+	err := query.Preload("Post.Author").Preload("Post.Tags").
+		Where("series_posts.series_id = ?", seriesID).
+		Order("series_posts.position ASC").
+		Find(&parts).Error
+	return parts, err
+}
+
+// 重新編號系列中文章的位置，order 中的文章排在前面，其餘（如回收站中的文章）按原順序排在後面
+func renumberSeriesPosts(tx *gorm.DB, seriesID uint, order []uint) error {
+	var rest []uint
+	if err := tx.Model(&models.SeriesPost{}).
+		Where("series_id = ? AND post_id NOT IN ?", seriesID, append([]uint{0}, order...)).
+		Order("position ASC").
+		Pluck("post_id", &rest).Error; err != nil {
+		return err
+	}
+	for i, postID := range append(order, rest...) {
+		if err := tx.Model(&models.SeriesPost{}).
+			Where("series_id = ? AND post_id = ?", seriesID, postID).
+			Update("position", i+1).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 系列中未刪除文章的當前順序
+func seriesPostOrder(tx *gorm.DB, seriesID uint) ([]uint, error) {
+	var order []uint
+	err := tx.Model(&models.SeriesPost{}).
+		Joins("JOIN posts ON posts.id = series_posts.post_id AND posts.deleted_at IS NULL").
+		Where("series_posts.series_id = ?", seriesID).
+		Order("series_posts.position ASC").
+		Pluck("series_posts.post_id", &order).Error
+	return order, err
+}
+
+// AddPost 將文章加入系列，position 從 1 開始，為 0 或超出範圍時加到末尾
+// 文章已在此系列中時移動到指定位置
+func (s *SeriesService) AddPost(seriesID, postID uint, position int) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		var existing models.SeriesPost
+		err := tx.Where("post_id = ?", postID).First(&existing).Error
+		switch {
+		case err == nil && existing.SeriesID != seriesID:
+			return ErrPostInOtherSeries
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			part := models.SeriesPost{SeriesID: seriesID, PostID: postID}
+			if err := tx.Create(&part).Error; err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		}
+
+		order, err := seriesPostOrder(tx, seriesID)
+		if err != nil {
+			return err
+		}
+		order = moveID(order, postID, position)
+		return renumberSeriesPosts(tx, seriesID, order)
+	})
+}
+
+// 將 id 移動到 position（從 1 開始）
+func moveID(order []uint, id uint, position int) []uint {
+	moved := make([]uint, 0, len(order))
+	for _, v := range order {
+		if v != id {
+			moved = append(moved, v)
+		}
+	}
+	if position <= 0 || position > len(moved) {
+		return append(moved, id)
+	}
+	moved = append(moved[:position-1], append([]uint{id}, moved[position-1:]...)...)
+	return moved
+}
+
+// RemovePost 將文章移出系列
+func (s *SeriesService) RemovePost(seriesID, postID uint) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("series_id = ? AND post_id = ?", seriesID, postID).Delete(&models.SeriesPost{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrPostNotInSeries
+		}
+		return renumberSeriesPosts(tx, seriesID, nil)
+	})
+}
+
+// ReorderPosts 按給定順序重排系列中的文章，postIDs 需包含系列中全部未刪除的文章
+func (s *SeriesService) ReorderPosts(seriesID uint, postIDs []uint) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		current, err := seriesPostOrder(tx, seriesID)
+		if err != nil {
+			return err
+		}
+		if len(uniqueIDs(postIDs)) != len(postIDs) || len(postIDs) != len(current) {
+			return ErrSeriesOrderMismatch
+		}
+		inSeries := make(map[uint]bool, len(current))
+		for _, id := range current {
+			inSeries[id] = true
+		}
+		for _, id := range postIDs {
+			if !inSeries[id] {
+				return ErrSeriesOrderMismatch
+			}
+		}
+		return renumberSeriesPosts(tx, seriesID, postIDs)
+	})
+}
+
+// GetPostNav 獲取文章所屬系列及前後篇，文章不屬於任何系列時返回 nil
+// 只在讀者可以看到的已發布文章間導航，當前文章未發布時也計入，便於作者預覽
+func (s *SeriesService) GetPostNav(post *models.Post, reader Actor) (*models.SeriesNav, error) {
+	var part models.SeriesPost
+	err := database.DB.Where("post_id = ?", post.ID).First(&part).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var series models.Series
+	if err := database.DB.First(&series, part.SeriesID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var posts []models.SeriesNavPost
+	visible := visiblePublishedPosts(database.DB, reader).Or("posts.id = ?", post.ID)
+	if err := database.DB.Model(&models.SeriesPost{}).
+		Joins("JOIN posts ON posts.id = series_posts.post_id AND posts.deleted_at IS NULL").
+		Where("series_posts.series_id = ?", series.ID).
+		Where(visible).
+		Order("series_posts.position ASC").
+		Select("posts.id, posts.title, posts.slug").
+		Scan(&posts).Error; err != nil {
+		return nil, err
+	}
+
+	nav := &models.SeriesNav{ID: series.ID, Title: series.Title, Total: len(posts)}
+	for i := range posts {
+		if posts[i].ID != post.ID {
+			continue
+		}
+		nav.Position = i + 1
+		if i > 0 {
+			nav.Prev = &posts[i-1]
+		}
+		if i+1 < len(posts) {
+			nav.Next = &posts[i+1]
+		}
+	}
+	return nav, nil
+}
+
+// 永久刪除用戶的系列
+func purgeUserSeries(tx *gorm.DB, userID uint) error {
+	var ids []uint
+	if err := tx.Unscoped().Model(&models.Series{}).Where("author_id = ?", userID).Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := tx.Where("series_id IN ?", ids).Delete(&models.SeriesPost{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Delete(&models.Series{}, ids).Error
+}
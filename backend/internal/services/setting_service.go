@@ -0,0 +1,61 @@
+package services
+
+import (
+	"errors"
+	"strconv"
+
+	"backend/internal/cache"
+	"backend/internal/database"
+	"backend/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type SettingService struct{}
+
+func NewSettingService() *SettingService {
+	return &SettingService{}
+}
+
+// 緩存的設置值，同時緩存不存在的設置
+type cachedSetting struct {
+	Value string
+	Found bool
+}
+
+// 獲取設置值，不存在時返回默認值
+// 設置沒有寫入接口，直接修改數據庫後在緩存過期時間內生效
+func (s *SettingService) Get(key, defaultValue string) string {
+	var cached cachedSetting
+	if !cache.GetObject(settingCachePrefix+key, &cached) {
+		var setting models.Setting
+		err := database.DB.Where("key = ?", key).First(&setting).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return defaultValue
+		}
+		cached = cachedSetting{Value: setting.Value, Found: err == nil}
+		cache.SetObject(settingCachePrefix+key, cached, 0)
+	}
+	if !cached.Found {
+		return defaultValue
+	}
+	return cached.Value
+}
+
+// 獲取布爾類型設置
+func (s *SettingService) GetBool(key string, defaultValue bool) bool {
+	value, err := strconv.ParseBool(s.Get(key, ""))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// 獲取整數類型設置
+func (s *SettingService) GetInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(s.Get(key, ""))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"net/url"
+	"time"
+
+	"backend/config"
+	"backend/internal/database"
+	"backend/internal/models"
+	"backend/pkg/sitemap"
+	"backend/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// 單個站點地圖文件的最大地址數（協議上限）
+const MaxSitemapURLs = 50000
+
+// 站點地圖分區
+const (
+	SitemapSectionPosts      = "posts"
+	SitemapSectionCategories = "categories"
+	SitemapSectionTags       = "tags"
+)
+
+// 站點地圖分片
+type SitemapPart struct {
+	Section string
+	Page    int
+	LastMod time.Time
+}
+
+type SitemapService struct {
+	settingService *SettingService
+}
+
+func NewSitemapService() *SitemapService {
+	return &SitemapService{
+		settingService: NewSettingService(),
+	}
+}
+
+// 每個分片的地址數，可通過 sitemap_page_size 設置調小
+func (s *SitemapService) PageSize() int {
+	size := s.settingService.GetInt("sitemap_page_size", MaxSitemapURLs)
+	if size < 1 || size > MaxSitemapURLs {
+		return MaxSitemapURLs
+	}
+	return size
+}
+
+// 各分區的查詢
+func (s *SitemapService) sectionQuery(section string) *sitemapQuery {
+	switch section {
+	case SitemapSectionPosts:
+		return &sitemapQuery{
+			model: &models.Post{},
+			where: "status = ? AND visibility = ?",
+			args:  []interface{}{models.PostStatusPublished, models.PostVisibilityPublic},
+		}
+	case SitemapSectionCategories:
+		// 只列出有公開文章的分類與標籤，沒有文章的列表頁返回 404
+		return &sitemapQuery{
+			model: &models.Category{},
+			where: "id IN (?)",
+			args:  []interface{}{publicPosts().Select("category_id")},
+		}
+	case SitemapSectionTags:
+		return &sitemapQuery{
+			model: &models.Tag{},
+			where: "id IN (?)",
+			args: []interface{}{database.DB.Table("post_tags").Select("tag_id").
+				Where("post_id IN (?)", publicPosts().Select("id"))},
+		}
+	}
+	return nil
+}
+
+// 已發布的公開文章
+func publicPosts() *gorm.DB {
+	return visiblePublishedPosts(database.DB.Model(&models.Post{}), Actor{})
+}
+
+// CategoryURL 分類列表頁地址
+func CategoryURL(category *models.Category) string {
+	return config.AppConfig.SiteURL + "/categories/" + url.PathEscape(category.Name)
+}
+
+// TagURL 標籤列表頁地址
+func TagURL(tag *models.Tag) string {
+	return config.AppConfig.SiteURL + "/tags/" + url.PathEscape(tag.Name)
+}
+
+// GetCategoryByName 根據名稱獲取分類
+func (s *SitemapService) GetCategoryByName(name string) (*models.Category, error) {
+	var category models.Category
+	if err := database.DB.Where("name = ?", name).First(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// ListingPosts 列表頁中已發布的公開文章，按發布時間倒序分頁
+func (s *SitemapService) ListingPosts(filter FeedFilter, page, limit int) ([]models.Post, int64, error) {
+	var posts []models.Post
+	var total int64
+
+	query := applyFeedFilter(publicPosts(), filter)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Preload("Author").
+		Order("COALESCE(posts.published_at, posts.created_at) DESC").Order("posts.id DESC").
+		Offset(utils.GetOffset(page, limit)).Limit(limit).
+		Find(&posts).Error
+	return posts, total, err
+}
+
+type sitemapQuery struct {
+	model interface{}
+	where string
+	args  []interface{}
+}
+
+// 統計分區地址數與最後更新時間
+func (s *SitemapService) sectionStats(section string) (int64, time.Time, error) {
+	q := s.sectionQuery(section)
+	query := database.DB.Model(q.model)
+	if q.where != "" {
+		query = query.Where(q.where, q.args...)
+	}
+
+	var stats struct {
+		Total   int64
+		LastMod string
+	}
+	if err := query.Select("COUNT(*) AS total, COALESCE(MAX(updated_at), '') AS last_mod").Scan(&stats).Error; err != nil {
+		return 0, time.Time{}, err
+	}
+	return stats.Total, parseDBTime(stats.LastMod), nil
+}
+
+// SQLite 聚合結果以字符串返回，兼容常見的時間格式
+func parseDBTime(value string) time.Time {
+	for _, layout := range []string{"2006-01-02 15:04:05.999999999-07:00", time.RFC3339Nano, "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// Parts 列出所有分片，空分區不輸出
+func (s *SitemapService) Parts() ([]SitemapPart, int64, error) {
+	pageSize := int64(s.PageSize())
+
+	var parts []SitemapPart
+	var total int64
+	for _, section := range []string{SitemapSectionPosts, SitemapSectionCategories, SitemapSectionTags} {
+		count, lastMod, err := s.sectionStats(section)
+		if err != nil {
+			return nil, 0, err
+		}
+		total += count
+		for page := 1; int64(page-1)*pageSize < count; page++ {
+			parts = append(parts, SitemapPart{Section: section, Page: page, LastMod: lastMod})
+		}
+	}
+	return parts, total, nil
+}
+
+// URLs 獲取分片中的地址，分區不存在時返回 nil
+func (s *SitemapService) URLs(section string, page int) ([]sitemap.URL, error) {
+	q := s.sectionQuery(section)
+	if q == nil || page < 1 {
+		return nil, nil
+	}
+	pageSize := s.PageSize()
+
+	query := database.DB.Model(q.model).Order("id").Offset((page - 1) * pageSize).Limit(pageSize)
+	if q.where != "" {
+		query = query.Where(q.where, q.args...)
+	}
+
+	var urls []sitemap.URL
+	switch section {
+	case SitemapSectionPosts:
+		var posts []models.Post
+		if err := query.Select("id", "slug", "updated_at").Find(&posts).Error; err != nil {
+			return nil, err
+		}
+		for i := range posts {
+			urls = append(urls, sitemap.URL{Loc: PostURL(&posts[i]), LastMod: posts[i].UpdatedAt})
+		}
+	case SitemapSectionCategories:
+		var categories []models.Category
+		if err := query.Find(&categories).Error; err != nil {
+			return nil, err
+		}
+		for i := range categories {
+			urls = append(urls, sitemap.URL{Loc: CategoryURL(&categories[i]), LastMod: categories[i].UpdatedAt})
+		}
+	case SitemapSectionTags:
+		var tags []models.Tag
+		if err := query.Find(&tags).Error; err != nil {
+			return nil, err
+		}
+		for i := range tags {
+			urls = append(urls, sitemap.URL{Loc: TagURL(&tags[i]), LastMod: tags[i].UpdatedAt})
+		}
+	}
+	return urls, nil
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"path/filepath"
+	"testing"
+
+	"backend/config"
+	"backend/internal/database"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// 為測試創建獨立的臨時數據庫，替換全局連接與配置，測試結束後恢復
+func setupTestDB(t *testing.T, models ...interface{}) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatal(err)
+	}
+
+	prevDB, prevConfig := database.DB, config.AppConfig
+	database.DB = db
+	config.AppConfig = &config.Config{JWTSecret: "test-secret", SiteURL: "https://example.com"}
+	t.Cleanup(func() {
+		database.DB, config.AppConfig = prevDB, prevConfig
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
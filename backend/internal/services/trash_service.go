@@ -0,0 +1,363 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"backend/config"
+	"backend/internal/database"
+	"backend/internal/models"
+	"backend/internal/storage"
+	"backend/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUsernameTaken = errors.New("用戶名已被其他帳號使用")
+	ErrEmailTaken    = errors.New("郵箱已被其他帳號使用")
+	ErrUserHasPosts  = errors.New("用戶仍有未刪除的文章")
+)
+
+// 文章關聯數據表，永久刪除文章時一併清除
+var postOwnedModels = []interface{}{
+	&models.Comment{},
+	&models.Reaction{},
+	&models.Bookmark{},
+	&models.PostStatusHistory{},
+	&models.PostSlugRedirect{},
+	&models.PostRender{},
+	&models.PostCollaborator{},
+	&models.PreviewToken{},
+	&models.SeriesPost{},
+	&models.PostViewEvent{},
+	&models.PostDailyStat{},
+	&models.PostDailyVisitor{},
+	&models.PostReferrerStat{},
+	&models.PostTerm{},
+	&models.PostTextIndex{},
+	&models.PostSimilarity{},
+}
+
+// 回收站中的文章，附帶刪除時間與預計自動清理時間
+type TrashedPost struct {
+	models.Post
+	DeletedAt time.Time  `json:"deleted_at"`
+	PurgeAt   *time.Time `json:"purge_at,omitempty"`
+}
+
+// 回收站中的用戶
+type TrashedUser struct {
+	models.User
+	DeletedAt time.Time  `json:"deleted_at"`
+	PurgeAt   *time.Time `json:"purge_at,omitempty"`
+}
+
+type TrashService struct{}
+
+func NewTrashService() *TrashService {
+	return &TrashService{}
+}
+
+// 根據保留天數計算自動清理時間，未開啟自動清理時返回 nil
+func purgeTime(deletedAt gorm.DeletedAt) *time.Time {
+	days := config.AppConfig.TrashRetentionDays
+	if days <= 0 {
+		return nil
+	}
+	t := deletedAt.Time.AddDate(0, 0, days)
+	return &t
+}
+
+// 獲取回收站中的文章
+func (s *TrashService) GetDeletedPosts(page, limit int) ([]TrashedPost, int64, error) {
+	var posts []models.Post
+	var total int64
+
+	query := database.DB.Unscoped().Model(&models.Post{}).Where("posts.deleted_at IS NOT NULL")
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Preload("Author", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Order("posts.deleted_at DESC").Offset(utils.GetOffset(page, limit)).Limit(limit).Find(&posts).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]TrashedPost, len(posts))
+	for i, post := range posts {
+		items[i] = TrashedPost{Post: post, DeletedAt: post.DeletedAt.Time, PurgeAt: purgeTime(post.DeletedAt)}
+	}
+	return items, total, nil
+}
+
+// 獲取回收站中的用戶
+func (s *TrashService) GetDeletedUsers(page, limit int) ([]TrashedUser, int64, error) {
+	var users []models.User
+	var total int64
+
+	query := database.DB.Unscoped().Model(&models.User{}).Where("deleted_at IS NOT NULL")
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("deleted_at DESC").Offset(utils.GetOffset(page, limit)).Limit(limit).Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]TrashedUser, len(users))
+	for i, user := range users {
+		items[i] = TrashedUser{User: user, DeletedAt: user.DeletedAt.Time, PurgeAt: purgeTime(user.DeletedAt)}
+	}
+	return items, total, nil
+}
+
+// 獲取已刪除的文章
+func (s *TrashService) getDeletedPost(tx *gorm.DB, id uint) (*models.Post, error) {
+	var post models.Post
+	if err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&post, id).Error; err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+// 獲取已刪除的用戶
+func (s *TrashService) getDeletedUser(tx *gorm.DB, id uint) (*models.User, error) {
+	var user models.User
+	if err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// 恢復文章
+func (s *TrashService) RestorePost(id uint) (*models.Post, error) {
+	post, err := s.getDeletedPost(database.DB, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := database.DB.Unscoped().Model(post).Update("deleted_at", nil).Error; err != nil {
+		return nil, err
+	}
+	invalidatePosts(id)
+	return NewPostService().GetPostByID(id)
+}
+
+// RestoreUser 恢復用戶，username 與 email 不為空時同時改名，避免與現有帳號衝突
+func (s *TrashService) RestoreUser(id uint, username, email string) (*models.User, error) {
+	var restored models.User
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		user, err := s.getDeletedUser(tx, id)
+		if err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{"deleted_at": nil}
+		if username != "" {
+			user.Username = username
+			updates["username"] = username
+		}
+		if email != "" {
+			user.Email = email
+			updates["email"] = email
+		}
+
+		// 刪除期間用戶名或郵箱可能已被新帳號使用
+		var count int64
+		if err := tx.Model(&models.User{}).Where("username = ? AND id <> ?", user.Username, id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrUsernameTaken
+		}
+		if err := tx.Model(&models.User{}).Where("email = ? AND id <> ?", user.Email, id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrEmailTaken
+		}
+
+		if err := tx.Unscoped().Model(user).Updates(updates).Error; err != nil {
+			return err
+		}
+		return tx.First(&restored, id).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	invalidateUsers(id)
+	return &restored, nil
+}
+
+// 永久刪除文章及其關聯數據
+func purgePost(tx *gorm.DB, postID uint) error {
+	if err := tx.Exec("DELETE FROM post_tags WHERE post_id = ?", postID).Error; err != nil {
+		return err
+	}
+	for _, model := range postOwnedModels {
+		if err := tx.Unscoped().Where("post_id = ?", postID).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	if err := tx.Where("related_id = ?", postID).Delete(&models.PostSimilarity{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Delete(&models.Post{}, postID).Error
+}
+
+// PurgePost 永久刪除回收站中的文章
+func (s *TrashService) PurgePost(id uint) error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := s.getDeletedPost(tx, id); err != nil {
+			return err
+		}
+		return purgePost(tx, id)
+	})
+}
+
+// 永久刪除用戶：回收站中的文章一併刪除，仍有未刪除的文章時拒絕
+// 有回覆的評論保留為已刪除佔位，仍被其他文章引用的媒體轉給引用文章的作者，其餘數據清除
+// 返回需要從存儲中刪除的文件
+func purgeUser(tx *gorm.DB, userID uint) ([]string, error) {
+	var live int64
+	if err := tx.Model(&models.Post{}).Where("author_id = ?", userID).Count(&live).Error; err != nil {
+		return nil, err
+	}
+	if live > 0 {
+		return nil, ErrUserHasPosts
+	}
+
+	var postIDs []uint
+	if err := tx.Unscoped().Model(&models.Post{}).Where("author_id = ?", userID).Pluck("id", &postIDs).Error; err != nil {
+		return nil, err
+	}
+	for _, postID := range postIDs {
+		if err := purgePost(tx, postID); err != nil {
+			return nil, err
+		}
+	}
+
+	// 沒有回覆的評論直接刪除，有回覆的保留為佔位
+	if err := tx.Unscoped().
+		Where("user_id = ? AND NOT EXISTS (SELECT 1 FROM comments AS r WHERE r.parent_id = comments.id)", userID).
+		Delete(&models.Comment{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("user_id = ?", userID).Delete(&models.Comment{}).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Where("user_id = ?", userID).Delete(&models.Reaction{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("user_id = ?", userID).Delete(&models.Bookmark{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("user_id = ?", userID).Delete(&models.PostCollaborator{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("created_by = ?", userID).Delete(&models.PreviewToken{}).Error; err != nil {
+		return nil, err
+	}
+	if err := purgeUserSeries(tx, userID); err != nil {
+		return nil, err
+	}
+
+	var media []models.Media
+	if err := tx.Unscoped().Where("user_id = ?", userID).Find(&media).Error; err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, m := range media {
+		// 用戶的文章已刪除，剩下的引用來自其他用戶的文章
+		postIDs, err := findMediaReferences(tx, m.StorageKey)
+		if err != nil {
+			return nil, err
+		}
+		if len(postIDs) > 0 {
+			var authorID uint
+			if err := tx.Unscoped().Model(&models.Post{}).Where("id = ?", postIDs[0]).Pluck("author_id", &authorID).Error; err != nil {
+				return nil, err
+			}
+			if err := tx.Unscoped().Model(&m).Update("user_id", authorID).Error; err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := tx.Unscoped().Delete(&m).Error; err != nil {
+			return nil, err
+		}
+		keys = append(keys, m.StorageKey)
+	}
+
+	return keys, tx.Unscoped().Delete(&models.User{}, userID).Error
+}
+
+// 刪除存儲中的文件，失敗只記錄日誌
+func removeStoredFiles(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := storage.Store.Delete(ctx, key); err != nil {
+			log.Printf("刪除文件 %s 失敗: %v", key, err)
+		}
+	}
+}
+
+// PurgeUser 永久刪除回收站中的用戶
+func (s *TrashService) PurgeUser(ctx context.Context, id uint) error {
+	var keys []string
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := s.getDeletedUser(tx, id); err != nil {
+			return err
+		}
+		var err error
+		keys, err = purgeUser(tx, id)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	invalidateUsers(id)
+
+	removeStoredFiles(ctx, keys)
+	return nil
+}
+
+// PurgeExpired 永久刪除在回收站中超過保留期的文章與用戶
+func (s *TrashService) PurgeExpired(ctx context.Context, retention time.Duration) error {
+	before := time.Now().Add(-retention)
+
+	var postIDs []uint
+	if err := database.DB.Unscoped().Model(&models.Post{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).Pluck("id", &postIDs).Error; err != nil {
+		return err
+	}
+	for _, id := range postIDs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := database.DB.Transaction(func(tx *gorm.DB) error { return purgePost(tx, id) }); err != nil {
+			return err
+		}
+	}
+
+	// 仍有未刪除文章的用戶跳過，等待管理員處理其文章
+	var userIDs []uint
+	if err := database.DB.Unscoped().Model(&models.User{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Where("NOT EXISTS (SELECT 1 FROM posts WHERE posts.author_id = users.id AND posts.deleted_at IS NULL)").
+		Pluck("id", &userIDs).Error; err != nil {
+		return err
+	}
+	for _, id := range userIDs {
+		if err := s.PurgeUser(ctx, id); err != nil && !errors.Is(err, ErrUserHasPosts) {
+			return err
+		}
+	}
+
+	if len(postIDs) > 0 || len(userIDs) > 0 {
+		log.Printf("回收站自動清理：文章 %d 篇，用戶 %d 個", len(postIDs), len(userIDs))
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"backend/internal/database"
+	"backend/internal/models"
+	"backend/internal/storage"
+)
+
+func trashTestModels() []interface{} {
+	return append([]interface{}{&models.User{}, &models.Post{}, &models.Tag{}, &models.Series{}, &models.Media{}}, postOwnedModels...)
+}
+
+func setupTrashTest(t *testing.T) {
+	t.Helper()
+	setupTestDB(t, trashTestModels()...)
+	store, err := storage.NewLocalStorage(t.TempDir(), "/uploads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := storage.Store
+	storage.Store = store
+	t.Cleanup(func() { storage.Store = prev })
+}
+
+// 用戶被刪除超過保留期後，其未刪除的文章不會被自動清理
+func TestPurgeExpiredKeepsLivePosts(t *testing.T) {
+	setupTrashTest(t)
+
+	user := models.User{Username: "alice", Email: "alice@example.com", Password: "x"}
+	database.DB.Create(&user)
+	live := models.Post{Title: "live", Status: models.PostStatusPublished, AuthorID: user.ID}
+	database.DB.Create(&live)
+
+	deletedAt := time.Now().AddDate(0, 0, -60)
+	database.DB.Model(&user).Update("deleted_at", deletedAt)
+
+	if err := NewTrashService().PurgeExpired(context.Background(), 30*24*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	var count int64
+	database.DB.Model(&models.Post{}).Where("id = ?", live.ID).Count(&count)
+	if count != 1 {
+		t.Fatal("live post of deleted user was purged")
+	}
+	database.DB.Unscoped().Model(&models.User{}).Where("id = ?", user.ID).Count(&count)
+	if count != 1 {
+		t.Fatal("user with live posts was purged")
+	}
+
+	if err := NewTrashService().PurgeUser(context.Background(), user.ID); !errors.Is(err, ErrUserHasPosts) {
+		t.Fatalf("PurgeUser err = %v, want ErrUserHasPosts", err)
+	}
+
+	// 文章進入回收站後可以清理
+	database.DB.Delete(&live)
+	if err := NewTrashService().PurgeExpired(context.Background(), 30*24*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	database.DB.Unscoped().Model(&models.User{}).Where("id = ?", user.ID).Count(&count)
+	if count != 0 {
+		t.Fatal("user was not purged after their posts were trashed")
+	}
+}
+
+// 永久刪除用戶時，仍被其他用戶文章引用的媒體轉給引用文章的作者，其餘媒體與文件刪除
+func TestPurgeUserKeepsReferencedMedia(t *testing.T) {
+	setupTrashTest(t)
+	ctx := context.Background()
+
+	alice := models.User{Username: "alice", Email: "alice@example.com", Password: "x"}
+	bob := models.User{Username: "bob", Email: "bob@example.com", Password: "x"}
+	database.DB.Create(&alice)
+	database.DB.Create(&bob)
+
+	var media []models.Media
+	for _, key := range []string{"media/1/shared.png", "media/1/own.png", "media/1/unused.png"} {
+		if err := storage.Store.Put(ctx, key, strings.NewReader("data"), 4, "image/png"); err != nil {
+			t.Fatal(err)
+		}
+		m := models.Media{UserID: alice.ID, StorageKey: key}
+		database.DB.Create(&m)
+		media = append(media, m)
+	}
+	shared, own, unused := media[0], media[1], media[2]
+
+	// alice 自己回收站中的文章引用 own，bob 的文章引用 shared
+	trashed := models.Post{Title: "trashed", AuthorID: alice.ID, Content: "![](/uploads/" + own.StorageKey + ")"}
+	database.DB.Create(&trashed)
+	database.DB.Delete(&trashed)
+	database.DB.Create(&models.Post{Title: "bob", AuthorID: bob.ID, Content: "![](/uploads/" + shared.StorageKey + ")"})
+	database.DB.Delete(&alice)
+
+	if err := NewTrashService().PurgeUser(ctx, alice.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	var kept models.Media
+	if err := database.DB.First(&kept, shared.ID).Error; err != nil {
+		t.Fatalf("referenced media was deleted: %v", err)
+	}
+	if kept.UserID != bob.ID {
+		t.Errorf("referenced media owner = %d, want %d", kept.UserID, bob.ID)
+	}
+	if r, err := storage.Store.Get(ctx, shared.StorageKey); err != nil {
+		t.Errorf("referenced file was deleted: %v", err)
+	} else {
+		r.Close()
+	}
+
+	for _, m := range []models.Media{own, unused} {
+		var count int64
+		database.DB.Unscoped().Model(&models.Media{}).Where("id = ?", m.ID).Count(&count)
+		if count != 0 {
+			t.Errorf("media %s was not deleted", m.StorageKey)
+		}
+		if r, err := storage.Store.Get(ctx, m.StorageKey); err == nil {
+			r.Close()
+			t.Errorf("file %s was not deleted", m.StorageKey)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"backend/config"
+)
+
+var ErrTooManyUnlockAttempts = errors.New("密碼錯誤次數過多，請稍後再試")
+
+// 全局解鎖嘗試限制器
+var UnlockAttempts *UnlockLimiter
+
+// UnlockLimiter 限制密碼保護文章的解鎖嘗試
+// 每個鍵（文章與用戶、文章與 IP）從第一次嘗試起計時，窗口內失敗達到上限後鎖定至窗口結束，解鎖成功後清零
+type UnlockLimiter struct {
+	maxAttempts int
+	lockout     time.Duration
+
+	mu       sync.Mutex
+	attempts map[string]*unlockAttempt
+}
+
+type unlockAttempt struct {
+	failures int
+	expires  time.Time
+}
+
+// 初始化全局解鎖嘗試限制器
+func InitUnlockLimiter() {
+	UnlockAttempts = NewUnlockLimiter(config.AppConfig.PostUnlockMaxAttempts, config.AppConfig.PostUnlockLockout)
+}
+
+// NewUnlockLimiter 創建限制器，maxAttempts 為 0 時不限制
+func NewUnlockLimiter(maxAttempts int, lockout time.Duration) *UnlockLimiter {
+	return &UnlockLimiter{
+		maxAttempts: maxAttempts,
+		lockout:     lockout,
+		attempts:    make(map[string]*unlockAttempt),
+	}
+}
+
+// Attempt 記錄一次嘗試，任一鍵已被鎖定時不計入並返回剩餘的鎖定時間
+// 嘗試先計為失敗，成功後由 Reset 清除，並發的嘗試也不會超過上限
+func (l *UnlockLimiter) Attempt(keys ...string) (time.Duration, bool) {
+	if l.maxAttempts <= 0 {
+		return 0, true
+	}
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var wait time.Duration
+	for _, key := range keys {
+		a, ok := l.attempts[key]
+		if ok && now.Before(a.expires) && a.failures >= l.maxAttempts && a.expires.Sub(now) > wait {
+			wait = a.expires.Sub(now)
+		}
+	}
+	if wait > 0 {
+		return wait, false
+	}
+
+	for _, key := range keys {
+		a, ok := l.attempts[key]
+		if !ok || !now.Before(a.expires) {
+			a = &unlockAttempt{expires: now.Add(l.lockout)}
+			l.attempts[key] = a
+		}
+		a.failures++
+	}
+	return 0, true
+}
+
+// Reset 解鎖成功後清除失敗記錄
+func (l *UnlockLimiter) Reset(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, key := range keys {
+		delete(l.attempts, key)
+	}
+}
+
+// Sweep 清理已過期的記錄
+func (l *UnlockLimiter) Sweep(ctx context.Context) error {
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for key, a := range l.attempts {
+		if !now.Before(a.expires) {
+			delete(l.attempts, key)
+		}
+	}
+	return nil
+}
+
+// UnlockKeys 解鎖嘗試的限制鍵：同一篇文章分別按用戶與客戶端 IP 計數
+func UnlockKeys(postID, userID uint, clientIP string) []string {
+	return []string{
+		fmt.Sprintf("post:%d:user:%d", postID, userID),
+		fmt.Sprintf("post:%d:ip:%s", postID, clientIP),
+	}
+}
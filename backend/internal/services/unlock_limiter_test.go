@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUnlockLimiter(t *testing.T) {
+	l := NewUnlockLimiter(3, time.Minute)
+	alice := UnlockKeys(1, 2, "10.0.0.1")
+
+	for i := 0; i < 3; i++ {
+		if _, ok := l.Attempt(alice...); !ok {
+			t.Fatalf("attempt %d rejected", i+1)
+		}
+	}
+	wait, ok := l.Attempt(alice...)
+	if ok || wait <= 0 || wait > time.Minute {
+		t.Fatalf("4th attempt = %v, %v; want locked", wait, ok)
+	}
+
+	// 同一 IP 換用戶、同一用戶換 IP 都仍被鎖定；其他文章不受影響
+	if _, ok := l.Attempt(UnlockKeys(1, 3, "10.0.0.1")...); ok {
+		t.Error("same IP, other user: want locked")
+	}
+	if _, ok := l.Attempt(UnlockKeys(1, 2, "10.0.0.2")...); ok {
+		t.Error("same user, other IP: want locked")
+	}
+	if _, ok := l.Attempt(UnlockKeys(2, 2, "10.0.0.1")...); !ok {
+		t.Error("other post: want allowed")
+	}
+
+	// 解鎖成功後清零
+	bob := UnlockKeys(1, 4, "10.0.0.9")
+	l.Attempt(bob...)
+	l.Attempt(bob...)
+	l.Reset(bob...)
+	for i := 0; i < 3; i++ {
+		if _, ok := l.Attempt(bob...); !ok {
+			t.Fatalf("after reset attempt %d rejected", i+1)
+		}
+	}
+}
+
+func TestUnlockLimiterConcurrent(t *testing.T) {
+	l := NewUnlockLimiter(5, time.Minute)
+	keys := UnlockKeys(1, 2, "10.0.0.1")
+
+	var mu sync.Mutex
+	allowed := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, ok := l.Attempt(keys...); ok {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if allowed != 5 {
+		t.Errorf("allowed %d concurrent attempts, want 5", allowed)
+	}
+}
+
+func TestUnlockLimiterExpiry(t *testing.T) {
+	l := NewUnlockLimiter(1, 20*time.Millisecond)
+	keys := UnlockKeys(1, 2, "10.0.0.1")
+	l.Attempt(keys...)
+	if _, ok := l.Attempt(keys...); ok {
+		t.Fatal("want locked")
+	}
+	time.Sleep(30 * time.Millisecond)
+	l.Sweep(context.Background())
+	if len(l.attempts) != 0 {
+		t.Errorf("sweep left %d entries", len(l.attempts))
+	}
+	if _, ok := l.Attempt(keys...); !ok {
+		t.Error("want allowed after lockout")
+	}
+
+	// 上限為 0 時不限制
+	unlimited := NewUnlockLimiter(0, time.Minute)
+	for i := 0; i < 10; i++ {
+		if _, ok := unlimited.Attempt(keys...); !ok {
+			t.Fatal("unlimited limiter rejected")
+		}
+	}
+}
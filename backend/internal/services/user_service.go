@@ -2,7 +2,9 @@ package services
 
 import (
 	"errors"
+	"time"
 
+	"backend/internal/cache"
 	"backend/internal/database"
 	"backend/internal/models"
 	"backend/pkg/utils"
@@ -82,37 +84,77 @@ func (s *UserService) Register(username, email, password string) (*models.User,
 	return &user, nil
 }
 
+// 用戶列表篩選條件，零值表示不篩選
+type UserFilter struct {
+	Role           string
+	Status         string
+	UsernamePrefix string
+	CreatedFrom    *time.Time
+	CreatedTo      *time.Time
+}
+
+// IsEmpty 是否沒有任何篩選條件
+func (f UserFilter) IsEmpty() bool {
+	return f == UserFilter{}
+}
+
+// 應用用戶篩選條件
+func applyUserFilter(query *gorm.DB, filter UserFilter) *gorm.DB {
+	if filter.Role != "" {
+		query = query.Where("users.role = ?", filter.Role)
+	}
+	if filter.Status != "" {
+		query = query.Where("users.status = ?", filter.Status)
+	}
+	if filter.UsernamePrefix != "" {
+		query = query.Where("users.username LIKE ? ESCAPE '\\'", escapeLike(filter.UsernamePrefix)+"%")
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("users.created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("users.created_at < ?", *filter.CreatedTo)
+	}
+	return query
+}
+
 // 獲取用戶列表
-func (s *UserService) GetUsers(page, limit int) ([]models.User, int64, error) {
+func (s *UserService) GetUsers(page, limit int, filter UserFilter) ([]models.User, int64, error) {
 	var users []models.User
 	var total int64
 
 	offset := utils.GetOffset(page, limit)
+	query := applyUserFilter(database.DB.Model(&models.User{}), filter)
 
 	// 獲取總數
-	if err := database.DB.Model(&models.User{}).Count(&total).Error; err != nil {
+	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// 獲取用戶列表
-	if err := database.DB.Offset(offset).Limit(limit).Find(&users).Error; err != nil {
+	if err := query.Order("users.id ASC").Offset(offset).Limit(limit).Find(&users).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return users, total, nil
 }
 
-// 根據 ID 獲取用戶
+// 根據 ID 獲取用戶，優先讀取緩存
 func (s *UserService) GetUserByID(id uint) (*models.User, error) {
 	var user models.User
+	if cache.GetObject(userCacheKey(id), &user) {
+		return &user, nil
+	}
+	generation := cacheLoadStart()
 	if err := database.DB.First(&user, id).Error; err != nil {
 		return nil, err
 	}
+	cacheLoadDone(userCacheKey(id), generation, &user)
 	return &user, nil
 }
 
-// 更新用戶
-func (s *UserService) UpdateUser(id uint, updates map[string]interface{}) (*models.User, error) {
+// 更新用戶，version 為客戶端持有的版本號，與當前版本不一致時返回 ErrVersionConflict，為 0 時不校驗
+func (s *UserService) UpdateUser(id, version uint, updates map[string]interface{}) (*models.User, error) {
 	var user models.User
 	if err := database.DB.First(&user, id).Error; err != nil {
 		return nil, err
@@ -131,14 +173,26 @@ func (s *UserService) UpdateUser(id uint, updates map[string]interface{}) (*mode
 		}
 	}
 
-	if err := database.DB.Model(&user).Updates(updates).Error; err != nil {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// 校驗並遞增版本號，版本不一致說明用戶已被其他請求修改
+		if err := bumpVersion(tx, &models.User{}, user.ID, version); err != nil {
+			return err
+		}
+		return tx.Model(&user).Updates(updates).Error
+	})
+	if err != nil {
 		return nil, err
 	}
+	invalidateUsers(user.ID)
 
-	return &user, nil
+	return s.GetUserByID(user.ID)
 }
 
 // 刪除用戶
 func (s *UserService) DeleteUser(id uint) error {
-	return database.DB.Delete(&models.User{}, id).Error
+	if err := database.DB.Delete(&models.User{}, id).Error; err != nil {
+		return err
+	}
+	invalidateUsers(id)
+	return nil
 }
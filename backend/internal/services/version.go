@@ -0,0 +1,30 @@
+package services
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrVersionConflict 數據已被其他請求修改，客戶端持有的版本已過期
+var ErrVersionConflict = errors.New("數據已被修改，請刷新後重試")
+
+// 在事務中遞增版本號，expected 非 0 時要求當前版本與之一致
+// 條件更新在單條語句中完成，併發的更新只有一個能成功；記錄不存在時返回 gorm.ErrRecordNotFound
+func bumpVersion(tx *gorm.DB, model interface{}, id, expected uint) error {
+	query := tx.Model(model).Where("id = ?", id)
+	if expected != 0 {
+		query = query.Where("version = ?", expected)
+	}
+	result := query.UpdateColumn("version", gorm.Expr("version + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		if expected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return ErrVersionConflict
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"backend/config"
+	"backend/internal/models"
+)
+
+// 全局瀏覽量計數器
+var Views *ViewCounter
+
+// ViewCounter 在內存中匯總瀏覽量並定期批量寫入數據庫
+// 同一訪客在去重窗口內重複瀏覽同一篇文章只計一次，作者本人的瀏覽不計入
+// 計入的瀏覽同時生成匿名瀏覽事件，供每日統計使用
+type ViewCounter struct {
+	window           time.Duration
+	postService      *PostService
+	analyticsService *AnalyticsService
+
+	mu      sync.Mutex
+	pending map[uint]int64
+	events  []models.PostViewEvent
+	seen    map[string]time.Time
+}
+
+// 初始化全局瀏覽量計數器
+func InitViewCounter() {
+	Views = NewViewCounter(config.AppConfig.ViewDedupWindow)
+}
+
+func NewViewCounter(window time.Duration) *ViewCounter {
+	return &ViewCounter{
+		window:           window,
+		postService:      NewPostService(),
+		analyticsService: NewAnalyticsService(),
+		pending:          make(map[uint]int64),
+		seen:             make(map[string]time.Time),
+	}
+}
+
+// Record 記錄一次瀏覽，返回是否計入
+// viewer 為訪客標識，登錄用戶使用用戶ID，匿名訪客可使用 IP 與 User-Agent 的組合
+// referrer 為來源地址，只保留域名
+func (v *ViewCounter) Record(post *models.Post, viewer string, userID uint, referrer string) bool {
+	if userID != 0 && userID == post.AuthorID {
+		return false
+	}
+
+	key := fmt.Sprintf("%d:%s", post.ID, viewer)
+	now := time.Now()
+	date := now.UTC().Format(StatsDateFormat)
+	event := models.PostViewEvent{
+		PostID:      post.ID,
+		Date:        date,
+		VisitorHash: VisitorHash(viewer, date),
+		Referrer:    ReferrerDomain(referrer),
+		CreatedAt:   now,
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if last, ok := v.seen[key]; ok && now.Sub(last) < v.window {
+		return false
+	}
+	v.seen[key] = now
+	v.pending[post.ID]++
+	v.events = append(v.events, event)
+	return true
+}
+
+// Flush 將累積的瀏覽量寫入數據庫，失敗時保留到下次寫入
+func (v *ViewCounter) Flush(ctx context.Context) error {
+	v.mu.Lock()
+	counts, events := v.pending, v.events
+	v.pending = make(map[uint]int64)
+	v.events = nil
+
+	// 清理過期的去重記錄
+	now := time.Now()
+	for key, last := range v.seen {
+		if now.Sub(last) >= v.window {
+			delete(v.seen, key)
+		}
+	}
+	v.mu.Unlock()
+
+	if len(counts) == 0 {
+		return nil
+	}
+	if err := v.postService.AddViewCounts(counts); err != nil {
+		v.mu.Lock()
+		for id, n := range counts {
+			v.pending[id] += n
+		}
+		v.events = append(events, v.events...)
+		v.mu.Unlock()
+		return err
+	}
+	if err := v.analyticsService.SaveEvents(events); err != nil {
+		v.mu.Lock()
+		v.events = append(events, v.events...)
+		v.mu.Unlock()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package templates
+
+import (
+	"embed"
+	"html/template"
+	"net/url"
+	"time"
+)
+
+//go:embed *.html
+var files embed.FS
+
+// 模板中可用的函數
+var funcs = template.FuncMap{
+	"iso8601": func(t time.Time) string {
+		return t.UTC().Format(time.RFC3339)
+	},
+	"date": func(t time.Time) string {
+		return t.Format("2006-01-02")
+	},
+	"pathEscape": url.PathEscape,
+}
+
+// Pages 服務端渲染頁面模板
+var Pages = template.Must(template.New("").Funcs(funcs).ParseFS(files, "*.html"))
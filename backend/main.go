@@ -1,16 +1,29 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"backend/config"
+	"backend/internal/cache"
 	"backend/internal/database"
+	"backend/internal/jobs"
 	"backend/internal/router"
+	"backend/internal/services"
+	"backend/internal/storage"
 
 	"github.com/gin-gonic/gin"
 )
 
+// 關閉時等待請求與後台任務完成的最長時間
+const shutdownTimeout = 15 * time.Second
+
 func main() {
 	// 載入配置
 	config.LoadConfig()
@@ -21,15 +34,76 @@ func main() {
 	// 初始化數據庫
 	database.InitDB()
 
+	// 初始化文件存儲
+	storage.InitStorage()
+
+	// 初始化查詢緩存
+	cache.InitCache()
+
+	// 補全舊文章的 slug
+	if err := services.NewPostService().EnsureSlugs(); err != nil {
+		log.Println("生成文章 slug 失敗:", err)
+	}
+
+	// 啟動後台任務
+	services.InitViewCounter()
+	services.InitUnlockLimiter()
+	scheduler := jobs.NewScheduler()
+	scheduler.Every("flush-views", config.AppConfig.ViewFlushInterval, services.Views.Flush)
+	scheduler.OnStop("flush-views", services.Views.Flush)
+	analyticsService := services.NewAnalyticsService()
+	rollup := func(ctx context.Context) error {
+		return analyticsService.RollupEvents()
+	}
+	scheduler.Go("rollup-analytics", rollup)
+	scheduler.Every("rollup-analytics", config.AppConfig.AnalyticsRollupInterval, rollup)
+	if days := config.AppConfig.TrashRetentionDays; days > 0 {
+		trashService := services.NewTrashService()
+		purge := func(ctx context.Context) error {
+			return trashService.PurgeExpired(ctx, time.Duration(days)*24*time.Hour)
+		}
+		scheduler.Go("purge-trash", purge)
+		scheduler.Every("purge-trash", config.AppConfig.TrashPurgeInterval, purge)
+	}
+	relatedService := services.NewRelatedService()
+	scheduler.Go("index-related", relatedService.IndexPending)
+	scheduler.Every("index-related", config.AppConfig.RelatedIndexInterval, relatedService.IndexPending)
+	if config.AppConfig.PostUnlockMaxAttempts > 0 && config.AppConfig.PostUnlockLockout > 0 {
+		scheduler.Every("sweep-unlock-attempts", config.AppConfig.PostUnlockLockout, services.UnlockAttempts.Sweep)
+	}
+	services.InitImportRunner()
+	scheduler.Go("imports", services.Imports.Run)
+
 	// 創建並初始化路由器
 	r := router.NewRouter()
 	engine := r.Initialize()
 
 	// 啟動服務器
-	addr := fmt.Sprintf(":%d", config.AppConfig.Port)
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%d", config.AppConfig.Port),
+		Handler: engine,
+	}
 	log.Printf("服務器啟動在端口 %d", config.AppConfig.Port)
 
-	if err := engine.Run(addr); err != nil {
-		log.Fatal("服務器啟動失敗:", err)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("服務器啟動失敗:", err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("正在關閉服務器...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	// 先停止接收請求，再停止後台任務並寫入剩餘數據
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Println("關閉服務器失敗:", err)
 	}
+	scheduler.Stop(shutdownCtx)
+	log.Println("服務器已關閉")
 }
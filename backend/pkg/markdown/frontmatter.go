@@ -0,0 +1,114 @@
+package markdown
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAML 前置元數據的分隔符
+const frontMatterDelimiter = "---"
+
+var ErrNoFrontMatter = errors.New("缺少 YAML 前置元數據")
+
+// SplitFrontMatter 拆分 Markdown 文件開頭以 --- 包圍的 YAML 元數據與正文
+func SplitFrontMatter(data []byte) (meta, body []byte, err error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+
+	if !bytes.HasPrefix(data, []byte(frontMatterDelimiter+"\n")) {
+		return nil, nil, ErrNoFrontMatter
+	}
+	rest := data[len(frontMatterDelimiter)+1:]
+	if bytes.HasPrefix(rest, []byte(frontMatterDelimiter+"\n")) {
+		return nil, bytes.TrimPrefix(rest[len(frontMatterDelimiter)+1:], []byte("\n")), nil
+	}
+
+	end := bytes.Index(rest, []byte("\n"+frontMatterDelimiter+"\n"))
+	if end < 0 {
+		// 文件以結束分隔符結尾、沒有正文
+		if !bytes.HasSuffix(rest, []byte("\n"+frontMatterDelimiter)) {
+			return nil, nil, errors.New("YAML 前置元數據未結束")
+		}
+		return rest[:len(rest)-len(frontMatterDelimiter)-1], nil, nil
+	}
+	return rest[:end], bytes.TrimPrefix(rest[end+len(frontMatterDelimiter)+2:], []byte("\n")), nil
+}
+
+// ParseFrontMatter 解析 Markdown 文件，元數據寫入 v，返回正文
+func ParseFrontMatter(data []byte, v interface{}) (string, error) {
+	meta, body, err := SplitFrontMatter(data)
+	if err != nil {
+		return "", err
+	}
+	if err := yaml.Unmarshal(meta, v); err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// FormatFrontMatter 生成帶 YAML 前置元數據的 Markdown 文件
+func FormatFrontMatter(v interface{}, body string) ([]byte, error) {
+	meta, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(frontMatterDelimiter + "\n")
+	buf.Write(meta)
+	buf.WriteString(frontMatterDelimiter + "\n\n")
+	buf.WriteString(body)
+	if body != "" && body[len(body)-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// 其他靜態站點生成器常用的時間格式，YAML 標準時間格式之外的補充
+var frontMatterTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05 -0700", // Jekyll
+	"2006-01-02 15:04:05 -07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// FrontMatterTime 寬鬆解析前置元數據中的時間，兼容 Hugo、Jekyll 等格式
+type FrontMatterTime struct {
+	time.Time
+}
+
+func (t *FrontMatterTime) UnmarshalYAML(node *yaml.Node) error {
+	value := strings.TrimSpace(node.Value)
+	for _, layout := range frontMatterTimeLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			t.Time = parsed
+			return nil
+		}
+	}
+	return fmt.Errorf("第 %d 行：無法解析時間 %q", node.Line, node.Value)
+}
+
+// FrontMatterList 字符串列表，也接受以逗號或空白分隔的單個字符串（Jekyll 寫法）
+type FrontMatterList []string
+
+func (l *FrontMatterList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*l = strings.FieldsFunc(node.Value, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+		return nil
+	}
+	var items []string
+	if err := node.Decode(&items); err != nil {
+		return err
+	}
+	*l = items
+	return nil
+}
@@ -0,0 +1,383 @@
+package markdown
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	spaceRe      = regexp.MustCompile(`[ \t\r\n\f]+`)
+	paragraphRe  = regexp.MustCompile(`\n[ \t]*\n`)
+	blankLinesRe = regexp.MustCompile(`\n{3,}`)
+	listMarkerRe = regexp.MustCompile(`^(\d+)\. `)
+	inlineEscape = strings.NewReplacer(`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", `&lt;`)
+)
+
+// 塊級元素，出現時結束當前段落
+var blockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Section: true, atom.Article: true, atom.Header: true, atom.Footer: true,
+	atom.Aside: true, atom.Nav: true, atom.Main: true, atom.Figure: true, atom.Figcaption: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Ul: true, atom.Ol: true, atom.Blockquote: true, atom.Pre: true, atom.Hr: true, atom.Table: true,
+	atom.Dl: true, atom.Dt: true, atom.Dd: true, atom.Address: true,
+}
+
+// 不輸出內容的元素
+var skippedElements = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true, atom.Head: true,
+}
+
+// FromHTML 將 HTML 轉換為 Markdown
+// 塊級元素外的文本按 WordPress 的自動分段規則處理：空行分段、單個換行為強制換行
+func FromHTML(src string) string {
+	nodes, err := html.ParseFragment(strings.NewReader(src), &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body})
+	if err != nil {
+		return src
+	}
+	root := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	for _, n := range nodes {
+		root.AppendChild(n)
+	}
+
+	out := strings.Join(convertBlocks(root, true), "\n\n")
+	return strings.TrimSpace(blankLinesRe.ReplaceAllString(out, "\n\n")) + "\n"
+}
+
+// 轉換子節點為塊列表，連續的行內內容合併為一個段落
+// autop 為 true 時按 WordPress 自動分段處理文本中的換行
+func convertBlocks(n *html.Node, autop bool) []string {
+	var blocks []string
+	var para strings.Builder
+
+	flush := func() {
+		if text := strings.TrimSpace(para.String()); text != "" {
+			blocks = append(blocks, escapeLineStart(text))
+		}
+		para.Reset()
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch {
+		case c.Type == html.TextNode && autop:
+			parts := paragraphRe.Split(c.Data, -1)
+			for i, part := range parts {
+				if i > 0 {
+					flush()
+				}
+				lines := strings.Split(part, "\n")
+				for j, line := range lines {
+					if j > 0 && strings.TrimSpace(para.String()) != "" && strings.TrimSpace(line) != "" {
+						para.WriteString("  \n")
+					}
+					para.WriteString(escapeText(line))
+				}
+			}
+		case c.Type == html.ElementNode && blockElements[c.DataAtom]:
+			flush()
+			if block := convertBlock(c); strings.TrimSpace(block) != "" {
+				blocks = append(blocks, block)
+			}
+		default:
+			para.WriteString(convertInline(c))
+		}
+	}
+	flush()
+	return blocks
+}
+
+// 轉換塊級元素
+func convertBlock(n *html.Node) string {
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		level := int(n.Data[1] - '0')
+		text := strings.TrimSpace(spaceRe.ReplaceAllString(inlineChildren(n), " "))
+		if text == "" {
+			return ""
+		}
+		return strings.Repeat("#", level) + " " + text
+
+	case atom.Ul, atom.Ol:
+		return convertList(n)
+
+	case atom.Blockquote:
+		inner := strings.Join(convertBlocks(n, false), "\n\n")
+		return prefixLines(inner, "> ", ">")
+
+	case atom.Pre:
+		return convertPre(n)
+
+	case atom.Hr:
+		return "---"
+
+	case atom.Table:
+		return convertTable(n)
+
+	case atom.Dt:
+		return "**" + strings.TrimSpace(inlineChildren(n)) + "**"
+	}
+	return strings.Join(convertBlocks(n, false), "\n\n")
+}
+
+// 轉換列表，嵌套內容按標記寬度縮進
+func convertList(n *html.Node) string {
+	ordered := n.DataAtom == atom.Ol
+	index := 1
+	if start, err := strconv.Atoi(attr(n, "start")); err == nil && ordered {
+		index = start
+	}
+
+	var items []string
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode || c.DataAtom != atom.Li {
+			continue
+		}
+		marker := "- "
+		if ordered {
+			marker = strconv.Itoa(index) + ". "
+			index++
+		}
+		// 緊跟文本的嵌套列表不空行，保持列表緊湊
+		var content string
+		for i, block := range convertBlocks(c, false) {
+			switch {
+			case i == 0:
+			case strings.HasPrefix(block, "- ") || block == "-" || listMarkerRe.MatchString(block):
+				content += "\n"
+			default:
+				content += "\n\n"
+			}
+			content += block
+		}
+		if content == "" {
+			items = append(items, strings.TrimSpace(marker))
+			continue
+		}
+		indent := strings.Repeat(" ", len(marker))
+		items = append(items, marker+prefixLines(content, indent, "")[len(indent):])
+	}
+	return strings.Join(items, "\n")
+}
+
+// 轉換預格式化文本為圍欄代碼塊
+func convertPre(n *html.Node) string {
+	lang := ""
+	target := n
+	if code := n.FirstChild; code != nil && code.DataAtom == atom.Code && code.NextSibling == nil {
+		target = code
+		for _, class := range strings.Fields(attr(code, "class")) {
+			if strings.HasPrefix(class, "language-") {
+				lang = strings.TrimPrefix(class, "language-")
+			}
+		}
+	}
+
+	code := strings.TrimRight(textContent(target), "\n")
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	return fence + lang + "\n" + code + "\n" + fence
+}
+
+// 轉換表格為 GFM 表格，第一行作為表頭
+func convertTable(n *html.Node) string {
+	var rows [][]string
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			if c.DataAtom == atom.Tr {
+				var cells []string
+				for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+					if cell.DataAtom == atom.Td || cell.DataAtom == atom.Th {
+						text := strings.TrimSpace(spaceRe.ReplaceAllString(inlineChildren(cell), " "))
+						cells = append(cells, strings.ReplaceAll(text, "|", `\|`))
+					}
+				}
+				rows = append(rows, cells)
+				continue
+			}
+			walk(c)
+		}
+	}
+	walk(n)
+	if len(rows) == 0 {
+		return ""
+	}
+
+	width := 0
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	var lines []string
+	for i, row := range rows {
+		for len(row) < width {
+			row = append(row, "")
+		}
+		lines = append(lines, "| "+strings.Join(row, " | ")+" |")
+		if i == 0 {
+			lines = append(lines, "|"+strings.Repeat(" --- |", width))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// 轉換行內節點
+func convertInline(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return escapeText(spaceRe.ReplaceAllString(n.Data, " "))
+	case html.ElementNode:
+	default:
+		return ""
+	}
+	if skippedElements[n.DataAtom] {
+		return ""
+	}
+
+	switch n.DataAtom {
+	case atom.Br:
+		return "  \n"
+	case atom.Strong, atom.B:
+		return wrapInline(inlineChildren(n), "**")
+	case atom.Em, atom.I:
+		return wrapInline(inlineChildren(n), "*")
+	case atom.Del, atom.S, atom.Strike:
+		return wrapInline(inlineChildren(n), "~~")
+	case atom.Code:
+		code := textContent(n)
+		fence := "`"
+		for strings.Contains(code, fence) {
+			fence += "`"
+		}
+		if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+			code = " " + code + " "
+		}
+		return fence + code + fence
+	case atom.A:
+		text := inlineChildren(n)
+		href := attr(n, "href")
+		if href == "" {
+			return text
+		}
+		if strings.TrimSpace(text) == "" {
+			text = escapeText(href)
+		}
+		return "[" + text + "](" + escapeURL(href) + titleSuffix(n) + ")"
+	case atom.Img:
+		src := attr(n, "src")
+		if src == "" {
+			return ""
+		}
+		return "![" + escapeText(attr(n, "alt")) + "](" + escapeURL(src) + titleSuffix(n) + ")"
+	case atom.Iframe, atom.Video, atom.Audio, atom.Embed:
+		if src := attr(n, "src"); src != "" {
+			return "[" + escapeText(src) + "](" + escapeURL(src) + ")"
+		}
+		return ""
+	}
+	return inlineChildren(n)
+}
+
+func inlineChildren(n *html.Node) string {
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && blockElements[c.DataAtom] {
+			// 行內上下文中的塊級元素以空格分隔
+			b.WriteString(" " + inlineChildren(c) + " ")
+			continue
+		}
+		b.WriteString(convertInline(c))
+	}
+	return b.String()
+}
+
+// 強調標記不能緊貼空白，將首尾空白移到標記外
+func wrapInline(text, mark string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	start := text[:strings.Index(text, trimmed)]
+	end := text[len(start)+len(trimmed):]
+	return start + mark + trimmed + mark + end
+}
+
+func titleSuffix(n *html.Node) string {
+	if title := attr(n, "title"); title != "" {
+		return ` "` + strings.ReplaceAll(title, `"`, `\"`) + `"`
+	}
+	return ""
+}
+
+func escapeText(s string) string {
+	return inlineEscape.Replace(s)
+}
+
+func escapeURL(s string) string {
+	return strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29").Replace(strings.TrimSpace(s))
+}
+
+// 段落開頭的 Markdown 塊標記需要轉義，避免被解析為標題、列表或引用
+func escapeLineStart(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		switch {
+		case strings.HasPrefix(trimmed, "#"), strings.HasPrefix(trimmed, ">"),
+			strings.HasPrefix(trimmed, "- "), strings.HasPrefix(trimmed, "+ "), trimmed == "-", trimmed == "---":
+			lines[i] = `\` + trimmed
+		case listMarkerRe.MatchString(trimmed):
+			m := listMarkerRe.FindStringSubmatch(trimmed)
+			lines[i] = m[1] + `\.` + trimmed[len(m[1])+1:]
+		default:
+			lines[i] = trimmed
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// 為每行添加前綴，空行使用 blank
+func prefixLines(s, prefix, blank string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = blank
+		} else {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.DataAtom == atom.Br {
+			b.WriteString("\n")
+			continue
+		}
+		b.WriteString(textContent(c))
+	}
+	return b.String()
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,46 @@
+package markdown
+
+import "testing"
+
+func TestFromHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"empty", "", "\n"},
+		{"autop paragraphs", "first line\nsecond line\n\nnext paragraph", "first line  \nsecond line\n\nnext paragraph\n"},
+		{"paragraph elements", "<p>one</p><p>two</p>", "one\n\ntwo\n"},
+		{"headings", "<h1>Title</h1><h3> Sub  <em>title</em> </h3>", "# Title\n\n### Sub *title*\n"},
+		{"empty heading dropped", "<h2> </h2><p>x</p>", "x\n"},
+		{"emphasis", "<p><strong>bold</strong>, <b>b</b>, <em>em</em>, <i>i</i>, <del>gone</del></p>", "**bold**, **b**, *em*, *i*, ~~gone~~\n"},
+		{"emphasis keeps spaces outside", "<p>a<strong> bold </strong>b</p>", "a **bold** b\n"},
+		{"escape inline", "<p>2*3 = a_b [x] &lt;tag&gt;</p>", "2\\*3 = a\\_b \\[x\\] &lt;tag>\n"},
+		{"escape line start", "<p># not heading</p><p>- not list</p><p>1. not ordered</p>", "\\# not heading\n\n\\- not list\n\n1\\. not ordered\n"},
+		{"link", `<p><a href="https://example.com/a b" title="T">text</a></p>`, "[text](https://example.com/a%20b \"T\")\n"},
+		{"link without text", `<a href="https://example.com"></a>`, "[https://example.com](https://example.com)\n"},
+		{"link without href", `<a name="x">anchor</a>`, "anchor\n"},
+		{"image", `<img src="/a.png" alt="Alt">`, "![Alt](/a.png)\n"},
+		{"iframe", `<iframe src="https://video.example/1"></iframe>`, "[https://video.example/1](https://video.example/1)\n"},
+		{"inline code", "<p>use <code>a`b</code></p>", "use ``a`b``\n"},
+		{"code block", `<pre><code class="language-go">fmt.Println("&lt;hi&gt;")
+</code></pre>`, "```go\nfmt.Println(\"<hi>\")\n```\n"},
+		{"code block with fence", "<pre>```\nx\n```</pre>", "````\n```\nx\n```\n````\n"},
+		{"unordered list", "<ul><li>a</li><li>b</li></ul>", "- a\n- b\n"},
+		{"ordered list with start", `<ol start="3"><li>c</li><li>d</li></ol>`, "3. c\n4. d\n"},
+		{"nested list", "<ul><li>a<ul><li>b</li></ul></li><li>c</li></ul>", "- a\n  - b\n- c\n"},
+		{"blockquote", "<blockquote><p>q1</p><p>q2</p></blockquote>", "> q1\n>\n> q2\n"},
+		{"hr", "<p>a</p><hr><p>b</p>", "a\n\n---\n\nb\n"},
+		{"table", "<table><tr><th>h1</th><th>h2</th></tr><tr><td>a|b</td></tr></table>", "| h1 | h2 |\n| --- | --- |\n| a\\|b |  |\n"},
+		{"line break", "<p>a<br>b</p>", "a  \nb\n"},
+		{"skipped elements", "<p>a<script>evil()</script><style>p{}</style>b</p>", "ab\n"},
+		{"definition list", "<dl><dt>term</dt><dd>desc</dd></dl>", "**term**\n\ndesc\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FromHTML(tt.html); got != tt.want {
+				t.Errorf("FromHTML(%q)\n got %q\nwant %q", tt.html, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"backend/pkg/utils"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// 閱讀速度：中日韓文字每分鐘字數、其他語言每分鐘詞數
+const (
+	cjkCharsPerMinute   = 300
+	latinWordsPerMinute = 200
+)
+
+// 目錄項
+type Heading struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	ID    string `json:"id"`
+}
+
+// 渲染結果
+type Result struct {
+	HTML        string
+	TOC         []Heading
+	PlainText   string
+	WordCount   int
+	ReadingTime int // 分鐘
+}
+
+var (
+	md = goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	)
+
+	policy = newPolicy()
+
+	// 行內 HTML 中的 script 與 style 標籤，其間的文本不屬於正文
+	rawTagPattern = regexp.MustCompile(`(?i)^<(/?)(script|style)\b[^>]*?(/?)>$`)
+)
+
+// 在 UGC 策略基礎上允許標題錨點與代碼高亮的語言標記
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("id").Matching(regexp.MustCompile(`^[a-z0-9-]+$`)).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	return p
+}
+
+// 標題錨點生成器，中文標題轉為拼音
+type headingIDs struct {
+	used map[string]bool
+}
+
+func (h *headingIDs) Generate(value []byte, kind ast.NodeKind) []byte {
+	base := utils.Slugify(string(value))
+	if base == "" {
+		base = "heading"
+	}
+	id := base
+	for i := 1; h.used[id]; i++ {
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+	h.used[id] = true
+	return []byte(id)
+}
+
+func (h *headingIDs) Put(value []byte) {
+	h.used[string(value)] = true
+}
+
+// Render 將 Markdown 渲染為經過清理的 HTML，並生成目錄、純文本及閱讀統計
+func Render(source string) (*Result, error) {
+	src := []byte(source)
+	ctx := parser.NewContext(parser.WithIDs(&headingIDs{used: map[string]bool{}}))
+	doc := md.Parser().Parse(text.NewReader(src), parser.WithContext(ctx))
+
+	var buf bytes.Buffer
+	if err := md.Renderer().Render(&buf, src, doc); err != nil {
+		return nil, err
+	}
+
+	plain := plainText(doc, src)
+	words, cjk := countWords(plain)
+
+	return &Result{
+		HTML:        policy.Sanitize(buf.String()),
+		TOC:         tableOfContents(doc, src),
+		PlainText:   plain,
+		WordCount:   words + cjk,
+		ReadingTime: readingTime(words, cjk),
+	}, nil
+}
+
+// Summary 生成純文本摘要，按字符（rune）截斷，不會切斷多字節文字
+func Summary(source string, maxRunes int) string {
+	src := []byte(source)
+	doc := md.Parser().Parse(text.NewReader(src))
+	return Truncate(plainText(doc, src), maxRunes)
+}
+
+// PlainText 提取 Markdown 的純文本，略過代碼塊、HTML 與圖片
+func PlainText(source string) string {
+	src := []byte(source)
+	doc := md.Parser().Parse(text.NewReader(src))
+	return plainText(doc, src)
+}
+
+// Truncate 按字符截斷文本，超出時追加省略號
+func Truncate(s string, maxRunes int) string {
+	if utf8.RuneCountInString(s) <= maxRunes {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:maxRunes])) + "..."
+}
+
+// 收集標題生成目錄
+func tableOfContents(doc ast.Node, src []byte) []Heading {
+	var toc []Heading
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := n.(*ast.Heading)
+		if !entering || !ok {
+			return ast.WalkContinue, nil
+		}
+		id, _ := heading.AttributeString("id")
+		idBytes, _ := id.([]byte)
+		toc = append(toc, Heading{
+			Level: heading.Level,
+			Text:  inlineText(heading, src),
+			ID:    string(idBytes),
+		})
+		return ast.WalkSkipChildren, nil
+	})
+	return toc
+}
+
+// 記錄是否處於行內 script 或 style 標籤之間
+// goldmark 將行內 HTML 的開始與結束標籤解析為獨立的 RawHTML 節點，其間的內容仍是普通文本
+type rawTagSkipper struct {
+	depth int
+}
+
+// 遇到 RawHTML 節點時更新狀態
+func (s *rawTagSkipper) visit(node *ast.RawHTML, src []byte) {
+	var raw []byte
+	for i := 0; i < node.Segments.Len(); i++ {
+		segment := node.Segments.At(i)
+		raw = append(raw, segment.Value(src)...)
+	}
+	m := rawTagPattern.FindSubmatch(bytes.TrimSpace(raw))
+	if m == nil || len(m[3]) > 0 {
+		// 其他標籤或自閉合標籤
+		return
+	}
+	if len(m[1]) == 0 {
+		s.depth++
+	} else if s.depth > 0 {
+		s.depth--
+	}
+}
+
+func (s *rawTagSkipper) skipping() bool {
+	return s.depth > 0
+}
+
+// 提取純文本，略過代碼塊、HTML、script 與 style 的內容以及圖片
+func plainText(doc ast.Node, src []byte) string {
+	var sb strings.Builder
+	var raw rawTagSkipper
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		switch node := n.(type) {
+		case *ast.RawHTML:
+			if entering {
+				raw.visit(node, src)
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.FencedCodeBlock, *ast.CodeBlock, *ast.HTMLBlock, *ast.Image:
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			if entering && !raw.skipping() {
+				sb.Write(node.Segment.Value(src))
+				if node.SoftLineBreak() || node.HardLineBreak() {
+					sb.WriteByte(' ')
+				}
+			}
+		case *ast.String:
+			if entering && !raw.skipping() {
+				sb.Write(node.Value)
+			}
+		default:
+			if !entering && n.Type() == ast.TypeBlock {
+				sb.WriteByte(' ')
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// 提取行內節點文本
+func inlineText(n ast.Node, src []byte) string {
+	var sb strings.Builder
+	var raw rawTagSkipper
+	ast.Walk(n, func(child ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := child.(type) {
+		case *ast.RawHTML:
+			raw.visit(node, src)
+		case *ast.Text:
+			if !raw.skipping() {
+				sb.Write(node.Segment.Value(src))
+			}
+		case *ast.String:
+			if !raw.skipping() {
+				sb.Write(node.Value)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.TrimSpace(sb.String())
+}
+
+// 是否為中日韓文字，每個字計為一個詞
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// 統計詞數：返回非中日韓詞數與中日韓字數
+func countWords(s string) (words, cjk int) {
+	inWord := false
+	for _, r := range s {
+		switch {
+		case isCJK(r):
+			cjk++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				words++
+				inWord = true
+			}
+		case r == '\'' || r == '-':
+			// 詞內的撇號與連字符不斷詞
+		default:
+			inWord = false
+		}
+	}
+	return words, cjk
+}
+
+// 估算閱讀時間（分鐘），有內容時至少一分鐘
+func readingTime(words, cjk int) int {
+	if words == 0 && cjk == 0 {
+		return 0
+	}
+	minutes := float64(words)/latinWordsPerMinute + float64(cjk)/cjkCharsPerMinute
+	return int(math.Max(1, math.Ceil(minutes)))
+}
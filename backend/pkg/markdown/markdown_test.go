@@ -0,0 +1,47 @@
+package markdown
+
+import "testing"
+
+func TestSummarySkipsRawHTML(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{"plain", "Hello **world**", "Hello world"},
+		{"inline script", "before <script>alert(1)</script> after", "before after"},
+		{"inline style", "a <style>p{color:red}</style>b", "a b"},
+		{"uppercase", "x <SCRIPT type=\"text/javascript\">evil()</SCRIPT> y", "x y"},
+		{"nested", "x <script><script>a</script>b</script>c", "x c"},
+		{"other tags keep text", "<span>kept</span> text", "kept text"},
+		{"self closing", "x <script src=\"x.js\"/> visible", "x visible"},
+		{"script block", "<script>\nalert(1)\n</script>\n\nbody", "body"},
+		{"code span", "use `<script>` tag", "use <script> tag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Summary(tt.source, 100); got != tt.want {
+				t.Errorf("Summary(%q) = %q, want %q", tt.source, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateRunes(t *testing.T) {
+	if got := Truncate("中文摘要測試", 4); got != "中文摘要..." {
+		t.Errorf("Truncate = %q", got)
+	}
+	if got := Truncate("short", 10); got != "short" {
+		t.Errorf("Truncate = %q", got)
+	}
+}
+
+func TestRenderTOCSkipsRawHTML(t *testing.T) {
+	result, err := Render("# Title <style>h1{}</style>\n\ntext")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.TOC) != 1 || result.TOC[0].Text != "Title" {
+		t.Errorf("TOC = %+v", result.TOC)
+	}
+}
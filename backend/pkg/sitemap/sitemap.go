@@ -0,0 +1,63 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// 站點地圖中的地址
+type URL struct {
+	Loc     string
+	LastMod time.Time
+}
+
+type urlSet struct {
+	XMLName xml.Name   `xml:"urlset"`
+	XMLNS   string     `xml:"xmlns,attr"`
+	URLs    []urlEntry `xml:"url"`
+}
+
+type urlEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name   `xml:"sitemapindex"`
+	XMLNS    string     `xml:"xmlns,attr"`
+	Sitemaps []urlEntry `xml:"sitemap"`
+}
+
+// URLSet 輸出 urlset 文件
+func URLSet(urls []URL) ([]byte, error) {
+	doc := urlSet{XMLNS: namespace, URLs: entries(urls)}
+	return marshal(doc)
+}
+
+// Index 輸出 sitemapindex 文件，urls 為各分片地址
+func Index(urls []URL) ([]byte, error) {
+	doc := sitemapIndex{XMLNS: namespace, Sitemaps: entries(urls)}
+	return marshal(doc)
+}
+
+func entries(urls []URL) []urlEntry {
+	result := make([]urlEntry, 0, len(urls))
+	for _, u := range urls {
+		entry := urlEntry{Loc: u.Loc}
+		if !u.LastMod.IsZero() {
+			entry.LastMod = u.LastMod.UTC().Format(time.RFC3339)
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+func marshal(v interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
@@ -0,0 +1,99 @@
+package tfidf
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// 詞的最大長度（字節），過長的多為鏈接或編碼數據
+const maxTermLen = 64
+
+// 常見英文停用詞
+var stopWords = map[string]bool{}
+
+func init() {
+	for _, w := range strings.Fields(`a about all also an and any are as at be been but by can could did do does
+		for from had has have he her him his how i if in into is it its just may more most no not of on only or other
+		our out she so some such than that the their them then there these they this those to too up us was we were
+		what when where which while who why will with would you your`) {
+		stopWords[w] = true
+	}
+}
+
+// 是否為中日韓文字
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// Terms 分詞並統計詞頻
+// 字母與數字按詞切分並轉為小寫，忽略停用詞、單個字母與純數字；
+// 中日韓文字沒有分隔符，按相鄰兩字切分，單獨的一個字作為一個詞
+func Terms(text string) map[string]int {
+	counts := make(map[string]int)
+
+	var word []rune
+	flushWord := func() {
+		if len(word) == 0 {
+			return
+		}
+		term := strings.ToLower(string(word))
+		word = word[:0]
+		if len(term) < 2 || len(term) > maxTermLen || stopWords[term] || isNumber(term) {
+			return
+		}
+		counts[term]++
+	}
+
+	var run []rune
+	flushRun := func() {
+		switch len(run) {
+		case 0:
+			return
+		case 1:
+			counts[string(run)]++
+		default:
+			for i := 0; i+1 < len(run); i++ {
+				counts[string(run[i:i+2])]++
+			}
+		}
+		run = run[:0]
+	}
+
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			flushWord()
+			run = append(run, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushRun()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushRun()
+		}
+	}
+	flushWord()
+	flushRun()
+	return counts
+}
+
+func isNumber(s string) bool {
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// Weight 計算詞的 TF-IDF 權重
+// 詞頻取對數避免長文章佔優，逆文檔頻率做平滑處理，出現在所有文檔中的詞權重仍大於 0
+func Weight(count int, docFreq, docs int64) float64 {
+	if count <= 0 {
+		return 0
+	}
+	tf := 1 + math.Log(float64(count))
+	idf := math.Log(float64(1+docs)/float64(1+docFreq)) + 1
+	return tf * idf
+}
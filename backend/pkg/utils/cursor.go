@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"backend/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+var ErrInvalidCursor = errors.New("無效的分頁游標")
+
+// EncodeCursor 將游標內容編碼為帶簽名的不透明字符串
+func EncodeCursor(payload interface{}) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	body := base64.RawURLEncoding.EncodeToString(data)
+	return body + "." + cursorSignature(body), nil
+}
+
+// DecodeCursor 校驗簽名並解碼游標
+func DecodeCursor(cursor string, payload interface{}) error {
+	body, sig, ok := strings.Cut(cursor, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(cursorSignature(body))) {
+		return ErrInvalidCursor
+	}
+	data, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, payload); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
+
+func cursorSignature(body string) string {
+	mac := hmac.New(sha256.New, []byte("cursor:"+config.AppConfig.JWTSecret))
+	mac.Write([]byte(body))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// GetCursorParams 獲取游標分頁參數，請求中帶有 cursor 參數（可為空，表示第一頁）時使用游標分頁
+func GetCursorParams(c *gin.Context) (cursor string, useCursor bool, limit int) {
+	cursor, useCursor = c.GetQuery("cursor")
+	_, limit = GetPaginationParams(c)
+	return cursor, useCursor, limit
+}
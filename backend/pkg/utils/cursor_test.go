@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"backend/config"
+)
+
+type testCursor struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
+}
+
+func withSecret(t *testing.T, secret string) {
+	t.Helper()
+	prev := config.AppConfig
+	config.AppConfig = &config.Config{JWTSecret: secret}
+	t.Cleanup(func() { config.AppConfig = prev })
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	withSecret(t, "secret")
+
+	want := testCursor{Sort: "-created_at,-id", Values: []string{"2024-05-01T00:00:00Z", "42"}}
+	cursor, err := EncodeCursor(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got testCursor
+	if err := DecodeCursor(cursor, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodeCursor = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodeCursorRejects(t *testing.T) {
+	withSecret(t, "secret")
+
+	cursor, err := EncodeCursor(testCursor{Sort: "id", Values: []string{"1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, sig, _ := strings.Cut(cursor, ".")
+
+	// 修改內容但沿用原簽名
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"s":"id","v":["999"]}`))
+	// 簽名正確但內容無法解碼
+	garbage := "!!!"
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"empty", ""},
+		{"missing dot", body + sig},
+		{"tampered body", forged + "." + sig},
+		{"tampered signature", body + "." + strings.ToUpper(sig)},
+		{"missing signature", body + "."},
+		{"bad base64", garbage + "." + cursorSignature(garbage)},
+		{"bad json", "bm90LWpzb24." + cursorSignature("bm90LWpzb24")},
+	}
+	for _, tt := range tests {
+		var got testCursor
+		if err := DecodeCursor(tt.cursor, &got); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%s: error = %v, want ErrInvalidCursor", tt.name, err)
+		}
+	}
+
+	// 密鑰變更後舊游標失效
+	withSecret(t, "rotated")
+	var got testCursor
+	if err := DecodeCursor(cursor, &got); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("rotated secret: error = %v, want ErrInvalidCursor", err)
+	}
+}
@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContentETag 根據內容生成強 ETag
+func ContentETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// CheckNotModified 設置 ETag 與 Last-Modified，並按條件請求頭判斷客戶端緩存是否仍然有效
+// 返回 true 時已寫入 304 響應，調用方應直接返回
+func CheckNotModified(c *gin.Context, etag string, lastModified time.Time) bool {
+	if etag != "" {
+		c.Header("ETag", etag)
+	}
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	// 同時提供時 If-None-Match 優先
+	if inm := c.GetHeader("If-None-Match"); inm != "" {
+		if etag != "" && etagMatches(inm, etag) {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+		return false
+	}
+
+	if ims := c.GetHeader("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(ims)
+		if err == nil && !lastModified.Truncate(time.Second).After(since) {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// If-None-Match 使用弱比較
+func etagMatches(header, etag string) bool {
+	target := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == target {
+			return true
+		}
+	}
+	return false
+}
+
+// WeakETag 根據內容生成弱 ETag，用於列表等只需語義等價的響應
+func WeakETag(body []byte) string {
+	return "W/" + ContentETag(body)
+}
+
+// ResourceETag 單條數據的強 ETag，格式為 "v<版本號>-<內容摘要>"
+// 內容摘要覆蓋瀏覽量等不遞增版本號的字段，版本號部分用於 If-Match 校驗
+func ResourceETag(version uint, body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"v` + strconv.FormatUint(uint64(version), 10) + "-" + hex.EncodeToString(sum[:8]) + `"`
+}
+
+var errInvalidIfMatch = errors.New("無效的 If-Match 請求頭")
+
+// ParseIfMatch 解析 If-Match 請求頭中的版本號，只比較 ETag 的版本號部分
+// 未提供時 ok 為 false；值為 * 時返回 0，表示不校驗版本；弱 ETag 或格式錯誤時返回錯誤
+func ParseIfMatch(c *gin.Context) (version uint, ok bool, err error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		return 0, false, nil
+	}
+	if header == "*" {
+		return 0, true, nil
+	}
+	if len(header) < 4 || !strings.HasPrefix(header, `"v`) || !strings.HasSuffix(header, `"`) {
+		return 0, true, errInvalidIfMatch
+	}
+	value := header[2 : len(header)-1]
+	if i := strings.IndexByte(value, '-'); i >= 0 {
+		value = value[:i]
+	}
+	n, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || n == 0 {
+		return 0, true, errInvalidIfMatch
+	}
+	return uint(n), true, nil
+}
+
+// 輸出 JSON 響應，按響應體生成 ETag
+// GET/HEAD 請求的客戶端緩存仍然有效時返回 304，其他請求只設置響應頭
+func writeCachedJSON(c *gin.Context, code int, obj interface{}, etag func(body []byte) string, lastModified time.Time) {
+	body, err := json.Marshal(obj)
+	if err != nil {
+		ErrorResponse(c, http.StatusInternalServerError, "生成響應失敗")
+		return
+	}
+
+	tag := etag(body)
+	if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+		if CheckNotModified(c, tag, lastModified) {
+			return
+		}
+	} else {
+		c.Header("ETag", tag)
+		if !lastModified.IsZero() {
+			c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+		}
+	}
+	c.Data(code, "application/json; charset=utf-8", body)
+}
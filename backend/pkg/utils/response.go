@@ -3,6 +3,7 @@ package utils
 import (
 	"math"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -14,14 +15,15 @@ type Response struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
-// 分頁響應結構
+// 分頁響應結構，Meta 為 PaginationMeta 或 CursorMeta
 type PaginatedResponse struct {
-	Code    int            `json:"code"`
-	Message string         `json:"message"`
-	Data    interface{}    `json:"data"`
-	Meta    PaginationMeta `json:"meta"`
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data"`
+	Meta    interface{} `json:"meta"`
 }
 
+// 頁碼分頁信息
 type PaginationMeta struct {
 	CurrentPage int   `json:"current_page"`
 	PerPage     int   `json:"per_page"`
@@ -29,6 +31,14 @@ type PaginationMeta struct {
 	TotalPages  int   `json:"total_pages"`
 }
 
+// 游標分頁信息，沒有更多數據時對應游標為空
+type CursorMeta struct {
+	PerPage    int    `json:"per_page"`
+	NextCursor string `json:"next_cursor"`
+	PrevCursor string `json:"prev_cursor"`
+	HasMore    bool   `json:"has_more"`
+}
+
 // 成功響應
 func SuccessResponse(c *gin.Context, data interface{}) {
 	c.JSON(200, Response{
@@ -38,6 +48,17 @@ func SuccessResponse(c *gin.Context, data interface{}) {
 	})
 }
 
+// 單條數據的成功響應，帶包含版本號的強 ETag 與 Last-Modified，客戶端緩存有效時返回 304
+func ResourceResponse(c *gin.Context, data interface{}, version uint, lastModified time.Time) {
+	writeCachedJSON(c, 200, Response{
+		Code:    200,
+		Message: "success",
+		Data:    data,
+	}, func(body []byte) string {
+		return ResourceETag(version, body)
+	}, lastModified)
+}
+
 // 錯誤響應
 func ErrorResponse(c *gin.Context, code int, message string) {
 	c.JSON(code, Response{
@@ -46,11 +67,11 @@ func ErrorResponse(c *gin.Context, code int, message string) {
 	})
 }
 
-// 分頁響應
+// 分頁響應，帶弱 ETag，客戶端緩存有效時返回 304
 func PaginatedSuccessResponse(c *gin.Context, data interface{}, currentPage, perPage int, total int64) {
 	totalPages := int(math.Ceil(float64(total) / float64(perPage)))
 
-	c.JSON(200, PaginatedResponse{
+	writeCachedJSON(c, 200, PaginatedResponse{
 		Code:    200,
 		Message: "success",
 		Data:    data,
@@ -60,7 +81,22 @@ func PaginatedSuccessResponse(c *gin.Context, data interface{}, currentPage, per
 			Total:       total,
 			TotalPages:  totalPages,
 		},
-	})
+	}, WeakETag, time.Time{})
+}
+
+// 游標分頁響應，帶弱 ETag，客戶端緩存有效時返回 304
+func CursorPaginatedSuccessResponse(c *gin.Context, data interface{}, perPage int, nextCursor, prevCursor string) {
+	writeCachedJSON(c, 200, PaginatedResponse{
+		Code:    200,
+		Message: "success",
+		Data:    data,
+		Meta: CursorMeta{
+			PerPage:    perPage,
+			NextCursor: nextCursor,
+			PrevCursor: prevCursor,
+			HasMore:    nextCursor != "",
+		},
+	}, WeakETag, time.Time{})
 }
 
 // 獲取分頁參數
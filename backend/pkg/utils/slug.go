@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mozillazg/go-pinyin"
+	"golang.org/x/text/unicode/norm"
+)
+
+// slug 最大長度
+const MaxSlugLength = 80
+
+// Slugify 將標題轉換為 URL 友好的 slug
+// 拉丁字母去除重音符號，中文轉換為不帶聲調的拼音，其餘字符作為分隔符
+func Slugify(title string) string {
+	args := pinyin.NewArgs()
+
+	var words []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+
+	var han []rune
+	flushHan := func() {
+		if len(han) > 0 {
+			words = append(words, pinyin.LazyPinyin(string(han), args)...)
+			han = han[:0]
+		}
+	}
+
+	// NFKD 分解後移除組合符號，例如 é → e
+	for _, r := range norm.NFKD.String(title) {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flush()
+			han = append(han, r)
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			flushHan()
+			word.WriteRune(unicode.ToLower(r))
+		default:
+			flushHan()
+			flush()
+		}
+	}
+	flushHan()
+	flush()
+
+	slug := strings.Join(words, "-")
+	if len(slug) > MaxSlugLength {
+		slug = strings.TrimRight(slug[:MaxSlugLength], "-")
+		if i := strings.LastIndex(slug, "-"); i > MaxSlugLength/2 {
+			slug = slug[:i]
+		}
+	}
+	return slug
+}
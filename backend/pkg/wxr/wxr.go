@@ -0,0 +1,170 @@
+// Package wxr 流式解析 WordPress 導出文件（WordPress eXtended RSS）
+//
+// 解碼器逐個返回站點作者、分類、標籤和文章，不會將整個文件載入內存。
+// WXR 的命名空間地址隨版本變化（export/1.0 ~ 1.2），因此只按元素本地名匹配。
+package wxr
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"time"
+)
+
+// WordPress 導出文件使用的時間格式（GMT）
+const timeLayout = "2006-01-02 15:04:05"
+
+// 作者
+type Author struct {
+	ID          int    `xml:"author_id"`
+	Login       string `xml:"author_login"`
+	Email       string `xml:"author_email"`
+	DisplayName string `xml:"author_display_name"`
+}
+
+// 分類
+type Category struct {
+	Nicename string `xml:"category_nicename"`
+	Parent   string `xml:"category_parent"`
+	Name     string `xml:"cat_name"`
+}
+
+// 標籤
+type Tag struct {
+	Slug string `xml:"tag_slug"`
+	Name string `xml:"tag_name"`
+}
+
+// 文章引用的分類或標籤
+type ItemTerm struct {
+	Domain   string `xml:"domain,attr"` // category 或 post_tag
+	Nicename string `xml:"nicename,attr"`
+	Name     string `xml:",chardata"`
+}
+
+// 評論
+type Comment struct {
+	ID          int    `xml:"comment_id"`
+	Author      string `xml:"comment_author"`
+	AuthorEmail string `xml:"comment_author_email"`
+	DateGMT     string `xml:"comment_date_gmt"`
+	Content     string `xml:"comment_content"`
+	Approved    string `xml:"comment_approved"` // 1、0、spam 或 trash
+	Type        string `xml:"comment_type"`     // 空、comment、pingback 或 trackback
+	Parent      int    `xml:"comment_parent"`
+	UserID      int    `xml:"comment_user_id"`
+}
+
+// 帶命名空間的 encoded 元素，content:encoded 為正文，excerpt:encoded 為摘要
+type encoded struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+// 文章、頁面或附件
+type Item struct {
+	Title       string     `xml:"title"`
+	Link        string     `xml:"link"`
+	GUID        string     `xml:"guid"`
+	Creator     string     `xml:"creator"`
+	ID          int        `xml:"post_id"`
+	DateGMT     string     `xml:"post_date_gmt"`
+	ModifiedGMT string     `xml:"post_modified_gmt"`
+	Name        string     `xml:"post_name"`
+	Status      string     `xml:"status"`
+	Type        string     `xml:"post_type"`
+	Terms       []ItemTerm `xml:"category"`
+	Comments    []Comment  `xml:"comment"`
+	Encoded     []encoded  `xml:"encoded"`
+}
+
+// Content 正文 HTML
+func (i *Item) Content() string {
+	return i.encoded("content")
+}
+
+// Excerpt 摘要 HTML
+func (i *Item) Excerpt() string {
+	return i.encoded("excerpt")
+}
+
+func (i *Item) encoded(kind string) string {
+	for _, e := range i.Encoded {
+		if strings.Contains(e.XMLName.Space, "/"+kind+"/") {
+			return e.Value
+		}
+	}
+	return ""
+}
+
+// ParseTime 解析 WordPress 的 GMT 時間，未設置（0000-00-00 00:00:00）時返回 nil
+func ParseTime(value string) *time.Time {
+	t, err := time.ParseInLocation(timeLayout, strings.TrimSpace(value), time.UTC)
+	if err != nil || t.Year() < 1970 {
+		return nil
+	}
+	return &t
+}
+
+// Decoder 流式解碼器
+type Decoder struct {
+	dec *xml.Decoder
+
+	// 站點標題，讀到 channel 的 title 後設置
+	SiteTitle string
+}
+
+func NewDecoder(r io.Reader) *Decoder {
+	dec := xml.NewDecoder(r)
+	// WordPress 導出文件可能包含不規範的實體；不啟用 AutoClose，RSS 的 link 元素並非空元素
+	dec.Strict = false
+	dec.Entity = xml.HTMLEntity
+	return &Decoder{dec: dec}
+}
+
+// Next 返回下一個條目（*Author、*Category、*Tag 或 *Item），讀完時返回 io.EOF
+func (d *Decoder) Next() (interface{}, error) {
+	for {
+		tok, err := d.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		var v interface{}
+		switch start.Name.Local {
+		case "title":
+			// 條目內的標題隨條目一起解碼，這裡只會遇到 channel 級別的標題
+			if d.SiteTitle == "" {
+				var title string
+				if err := d.dec.DecodeElement(&title, &start); err != nil {
+					return nil, err
+				}
+				d.SiteTitle = strings.TrimSpace(title)
+			}
+			continue
+		case "author":
+			v = &Author{}
+		case "category":
+			v = &Category{}
+		case "tag":
+			v = &Tag{}
+		case "item":
+			v = &Item{}
+		default:
+			continue
+		}
+		if err := d.dec.DecodeElement(v, &start); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+}
+
+// InputOffset 已讀取的字節數，用於計算進度
+func (d *Decoder) InputOffset() int64 {
+	return d.dec.InputOffset()
+}
@@ -0,0 +1,199 @@
+package wxr
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sample = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0"
+	xmlns:excerpt="http://wordpress.org/export/1.2/excerpt/"
+	xmlns:content="http://purl.org/rss/1.0/modules/content/"
+	xmlns:dc="http://purl.org/dc/elements/1.1/"
+	xmlns:wp="http://wordpress.org/export/1.2/">
+<channel>
+	<title> My Blog </title>
+	<link>https://blog.example.com</link>
+	<wp:author>
+		<wp:author_id>2</wp:author_id>
+		<wp:author_login><![CDATA[alice]]></wp:author_login>
+		<wp:author_email><![CDATA[alice@example.com]]></wp:author_email>
+		<wp:author_display_name>Alice &amp; Co</wp:author_display_name>
+	</wp:author>
+	<wp:category>
+		<wp:term_id>3</wp:term_id>
+		<wp:category_nicename><![CDATA[go]]></wp:category_nicename>
+		<wp:category_parent><![CDATA[programming]]></wp:category_parent>
+		<wp:cat_name><![CDATA[Go 語言]]></wp:cat_name>
+	</wp:category>
+	<wp:tag>
+		<wp:tag_slug><![CDATA[tips]]></wp:tag_slug>
+		<wp:tag_name><![CDATA[Tips]]></wp:tag_name>
+	</wp:tag>
+	<item>
+		<title>Hello&nbsp;World</title>
+		<link>https://blog.example.com/hello</link>
+		<guid isPermaLink="false">https://blog.example.com/?p=10</guid>
+		<dc:creator><![CDATA[alice]]></dc:creator>
+		<content:encoded><![CDATA[<p>Body</p>]]></content:encoded>
+		<excerpt:encoded><![CDATA[Short]]></excerpt:encoded>
+		<wp:post_id>10</wp:post_id>
+		<wp:post_date_gmt>2024-05-01 02:03:04</wp:post_date_gmt>
+		<wp:post_modified_gmt>0000-00-00 00:00:00</wp:post_modified_gmt>
+		<wp:post_name><![CDATA[hello-world]]></wp:post_name>
+		<wp:status>private</wp:status>
+		<wp:post_type>post</wp:post_type>
+		<category domain="category" nicename="go"><![CDATA[Go 語言]]></category>
+		<category domain="post_tag" nicename="tips"><![CDATA[Tips]]></category>
+		<wp:comment>
+			<wp:comment_id>5</wp:comment_id>
+			<wp:comment_author><![CDATA[Guest]]></wp:comment_author>
+			<wp:comment_date_gmt>2024-05-02 00:00:00</wp:comment_date_gmt>
+			<wp:comment_content><![CDATA[Nice]]></wp:comment_content>
+			<wp:comment_approved>1</wp:comment_approved>
+			<wp:comment_type>comment</wp:comment_type>
+			<wp:comment_parent>0</wp:comment_parent>
+			<wp:comment_user_id>0</wp:comment_user_id>
+		</wp:comment>
+		<wp:comment>
+			<wp:comment_id>6</wp:comment_id>
+			<wp:comment_content><![CDATA[Reply]]></wp:comment_content>
+			<wp:comment_approved>spam</wp:comment_approved>
+			<wp:comment_parent>5</wp:comment_parent>
+			<wp:comment_user_id>2</wp:comment_user_id>
+		</wp:comment>
+	</item>
+	<item>
+		<title>About</title>
+		<wp:post_id>11</wp:post_id>
+		<wp:post_type>page</wp:post_type>
+	</item>
+</channel>
+</rss>`
+
+func decodeAll(t *testing.T, src string) (*Decoder, []interface{}) {
+	t.Helper()
+	dec := NewDecoder(strings.NewReader(src))
+	var entries []interface{}
+	for {
+		v, err := dec.Next()
+		if errors.Is(err, io.EOF) {
+			return dec, entries
+		}
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		entries = append(entries, v)
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	dec, entries := decodeAll(t, sample)
+
+	if dec.SiteTitle != "My Blog" {
+		t.Errorf("SiteTitle = %q", dec.SiteTitle)
+	}
+	if dec.InputOffset() != int64(len(sample)) {
+		t.Errorf("InputOffset = %d, want %d", dec.InputOffset(), len(sample))
+	}
+	if len(entries) != 5 {
+		t.Fatalf("got %d entries, want 5: %#v", len(entries), entries)
+	}
+
+	want := []interface{}{
+		&Author{ID: 2, Login: "alice", Email: "alice@example.com", DisplayName: "Alice & Co"},
+		&Category{Nicename: "go", Parent: "programming", Name: "Go 語言"},
+		&Tag{Slug: "tips", Name: "Tips"},
+	}
+	for i, w := range want {
+		if !reflect.DeepEqual(entries[i], w) {
+			t.Errorf("entry %d = %#v, want %#v", i, entries[i], w)
+		}
+	}
+
+	item, ok := entries[3].(*Item)
+	if !ok {
+		t.Fatalf("entry 3 = %T, want *Item", entries[3])
+	}
+	checks := []struct {
+		field string
+		got   interface{}
+		want  interface{}
+	}{
+		{"Title", item.Title, "Hello\u00a0World"},
+		{"Link", item.Link, "https://blog.example.com/hello"},
+		{"GUID", item.GUID, "https://blog.example.com/?p=10"},
+		{"Creator", item.Creator, "alice"},
+		{"ID", item.ID, 10},
+		{"Name", item.Name, "hello-world"},
+		{"Status", item.Status, "private"},
+		{"Type", item.Type, "post"},
+		{"Content", item.Content(), "<p>Body</p>"},
+		{"Excerpt", item.Excerpt(), "Short"},
+		{"Terms", item.Terms, []ItemTerm{
+			{Domain: "category", Nicename: "go", Name: "Go 語言"},
+			{Domain: "post_tag", Nicename: "tips", Name: "Tips"},
+		}},
+		{"Comments", item.Comments, []Comment{
+			{ID: 5, Author: "Guest", DateGMT: "2024-05-02 00:00:00", Content: "Nice", Approved: "1", Type: "comment"},
+			{ID: 6, Content: "Reply", Approved: "spam", Parent: 5, UserID: 2},
+		}},
+	}
+	for _, c := range checks {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("Item.%s = %#v, want %#v", c.field, c.got, c.want)
+		}
+	}
+
+	page, ok := entries[4].(*Item)
+	if !ok || page.Type != "page" || page.ID != 11 || page.Content() != "" {
+		t.Errorf("entry 4 = %#v", entries[4])
+	}
+}
+
+func TestDecoderErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"truncated", `<rss><channel><item><title>x</title>`},
+		{"bad number", `<rss><channel><wp:author><wp:author_id>abc</wp:author_id></wp:author></channel></rss>`},
+	}
+	for _, tt := range tests {
+		dec := NewDecoder(strings.NewReader(tt.src))
+		var err error
+		for err == nil {
+			_, err = dec.Next()
+		}
+		if errors.Is(err, io.EOF) {
+			t.Errorf("%s: expected decode error, got EOF", tt.name)
+		}
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	tests := []struct {
+		value string
+		want  *time.Time
+	}{
+		{"2024-05-01 02:03:04", ptr(time.Date(2024, 5, 1, 2, 3, 4, 0, time.UTC))},
+		{" 2024-05-01 02:03:04\n", ptr(time.Date(2024, 5, 1, 2, 3, 4, 0, time.UTC))},
+		{"0000-00-00 00:00:00", nil},
+		{"", nil},
+		{"2024-05-01", nil},
+	}
+	for _, tt := range tests {
+		got := ParseTime(tt.value)
+		if (got == nil) != (tt.want == nil) || (got != nil && !got.Equal(*tt.want)) {
+			t.Errorf("ParseTime(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func ptr(t time.Time) *time.Time {
+	return &t
+}